@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/joce/dorfyn"
 )
@@ -23,4 +24,252 @@ func main() {
 		}
 		fmt.Println()
 	}
+
+	// Decimal quote example.
+	// ----------------------
+	{
+		q, err := dorfyn.GetDecimalQuotes([]string{"AAPL", "EURUSD=X"})
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			for _, v := range q {
+				fmt.Printf("%s: %s\n", *v.Symbol, v.RegularMarketPrice)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Quote view example.
+	// -------------------
+	{
+		q, err := dorfyn.GetQuotes([]string{"AAPL", "BTC-USD", "VT"})
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			for _, v := range dorfyn.QuoteViews(q) {
+				switch view := v.(type) {
+				case *dorfyn.EquityQuote:
+					if view.MarketCap != nil {
+						fmt.Printf("%s (equity): market cap %d\n", *view.Symbol, *view.MarketCap)
+					} else {
+						fmt.Printf("%s (equity): no market cap\n", *view.Symbol)
+					}
+				case *dorfyn.CryptoQuote:
+					if view.CirculatingSupply != nil {
+						fmt.Printf("%s (crypto): circulating supply %d\n", *view.Symbol, *view.CirculatingSupply)
+					} else {
+						fmt.Printf("%s (crypto): no circulating supply\n", *view.Symbol)
+					}
+				default:
+					fmt.Printf("%s (%s)\n", *v.Base().Symbol, *v.Base().QuoteType)
+				}
+			}
+		}
+		fmt.Println()
+	}
+
+	// Field selection example.
+	// ------------------------
+	{
+		q, err := dorfyn.GetQuotesFields([]string{"AAPL", "MSFT"},
+			dorfyn.QuoteFieldRegularMarketPrice, dorfyn.QuoteFieldRegularMarketChange, dorfyn.QuoteFieldMarketState)
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			for _, v := range q {
+				fmt.Printf("%s: %f (%+f) %s\n", *v.Symbol, *v.RegularMarketPrice, *v.RegularMarketChange, *v.MarketState)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Search example.
+	// ---------------
+	{
+		r, err := dorfyn.Search("apple", &dorfyn.SearchParams{QuotesCount: 5, NewsCount: 2})
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			for _, v := range r.Quotes {
+				fmt.Printf("%s: %s (%s, %s)\n", v.Symbol, v.ShortName, v.ExchangeDisplay, v.QuoteType)
+			}
+			for _, v := range r.News {
+				fmt.Printf("%s - %s\n", v.Title, v.Publisher)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Quote summary example.
+	// ----------------------
+	{
+		s, err := dorfyn.GetQuoteSummary("AAPL", dorfyn.SummaryModuleAssetProfile, dorfyn.SummaryModuleFinancialData)
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("%s / %s, %d employees\n", s.AssetProfile.Sector, s.AssetProfile.Industry, s.AssetProfile.FullTimeEmployees)
+			fmt.Printf("Target mean price: %s\n", s.FinancialData.TargetMeanPrice.Fmt)
+		}
+		fmt.Println()
+	}
+
+	// Financial statements example.
+	// -----------------------------
+	{
+		f, err := dorfyn.GetFinancials("AAPL", dorfyn.StatementIncome, &dorfyn.FinancialsParams{
+			Frequency: dorfyn.FrequencyQuarterly,
+			Items:     []dorfyn.LineItem{dorfyn.LineItemTotalRevenue, dorfyn.LineItemNetIncome},
+		})
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			for _, d := range f.Dates() {
+				revenue, _ := f.Value(dorfyn.LineItemTotalRevenue, d)
+				income, _ := f.Value(dorfyn.LineItemNetIncome, d)
+				fmt.Printf("%s: revenue %.0f, net income %.0f\n", d.Format("2006-01-02"), revenue, income)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Chart and corporate actions example.
+	// ------------------------------------
+	{
+		c, err := dorfyn.GetChart("AAPL", &dorfyn.ChartParams{Range: "5y"})
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("%d bars\n", len(c.Bars))
+			for _, s := range c.Splits {
+				fmt.Printf("Split %s on %s\n", s, s.Date.Format("2006-01-02"))
+			}
+			for _, d := range c.Dividends {
+				fmt.Printf("Dividend %s on %s\n", d.Amount, d.Date.Format("2006-01-02"))
+			}
+		}
+		fmt.Println()
+	}
+
+	// Long-range intraday history example.
+	// ------------------------------------
+	{
+		c, err := dorfyn.GetHistory("MSFT", dorfyn.HistoryParams{
+			Interval: dorfyn.Interval5Min,
+			Start:    time.Now().AddDate(0, 0, -55),
+		})
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("%d five minutes bars\n", len(c.Bars))
+		}
+		fmt.Println()
+	}
+
+	// Option surface example.
+	// -----------------------
+	{
+		s, err := dorfyn.GetOptionSurface("SPY", 0)
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			for _, p := range s.TermStructure(nil) {
+				fmt.Printf("%s: ATM IV %.2f%%\n", p.Expiration.Time().Format("2006-01-02"), p.ImpliedVolatility*100)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Streaming quotes example.
+	// -------------------------
+	{
+		s := dorfyn.NewStream(nil)
+		if err := s.Subscribe("BTC-USD", "ETH-USD"); err != nil {
+			fmt.Println(err)
+		}
+
+		timeout := time.After(10 * time.Second)
+	stream:
+		for n := 0; n < 5; {
+			select {
+			case t := <-s.Ticks():
+				fmt.Printf("%s %s: %.2f\n", t.Time.Time().Format(time.TimeOnly), t.Symbol, t.Price)
+				n++
+			case err := <-s.Errors():
+				fmt.Println(err)
+			case <-timeout:
+				break stream
+			}
+		}
+		s.Close()
+		fmt.Println()
+	}
+
+	// Quote watcher example.
+	// ----------------------
+	{
+		w, err := dorfyn.NewWatcher([]string{"AAPL", "MSFT"}, &dorfyn.WatcherParams{
+			Interval: 5 * time.Second,
+			Fields:   []dorfyn.QuoteField{dorfyn.QuoteFieldRegularMarketPrice, dorfyn.QuoteFieldRegularMarketVolume},
+		})
+
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			updates := w.Subscribe()
+			timeout := time.After(15 * time.Second)
+		watch:
+			for {
+				select {
+				case u := <-updates:
+					for _, c := range u.Changes {
+						fmt.Printf("%s %s: %v -> %v\n", u.Symbol, c.Field, c.Old, c.New)
+					}
+				case <-timeout:
+					break watch
+				}
+			}
+			w.Close()
+		}
+		fmt.Println()
+	}
+
+	// Response cache example.
+	// -----------------------
+	{
+		if err := dorfyn.EnableCache(&dorfyn.CacheParams{StaleWhileRevalidate: 30 * time.Second}); err != nil {
+			fmt.Println(err)
+		} else {
+			for i := 0; i < 3; i++ {
+				start := time.Now()
+				_, err := dorfyn.GetQuotes([]string{"AAPL"})
+				fmt.Printf("Quote fetched in %v (error: %v)\n", time.Since(start), err)
+			}
+			dorfyn.DisableCache()
+		}
+		fmt.Println()
+	}
+
+	// History store example.
+	// ----------------------
+	{
+		s, err := dorfyn.OpenHistoryStore("history", nil)
+
+		if err != nil {
+			fmt.Println(err)
+		} else if bars, err := s.Update("AAPL", dorfyn.Interval1Day, time.Now().AddDate(-5, 0, 0)); err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("%d daily bars stored for AAPL\n", len(bars))
+		}
+		fmt.Println()
+	}
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joce/dorfyn"
+	"github.com/shopspring/decimal"
+	_ "modernc.org/sqlite"
+)
+
+// This program exports a chart, a quote and an option chain to an in-memory SQLite database, twice, and checks that
+// reading them back gives a single copy of the exported data. It lives in its own module, so that the library doesn't
+// depend on any database driver. Run it with go run, or go test from its directory.
+func main() {
+	if err := run(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println("SQLite round trip OK")
+}
+
+func run() error {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	// Each connection to ":memory:" has its own database.
+	db.SetMaxOpenConns(1)
+
+	e := dorfyn.NewSQLExporter(db, &dorfyn.SQLExportParams{Dialect: dorfyn.SQLDialectSQLite, TablePrefix: "yf_"})
+	if err := e.CreateSchema(); err != nil {
+		return err
+	}
+	if err := e.ExportChart(nil); err == nil {
+		return fmt.Errorf("ExportChart accepted a nil chart")
+	}
+	if err := e.ExportOptionChain(nil, time.Time{}); err == nil {
+		return fmt.Errorf("ExportOptionChain accepted a nil chain")
+	}
+
+	chart := &dorfyn.Chart{
+		Bars: []dorfyn.ChartBar{
+			{
+				Timestamp: 1700000000,
+				Open:      decimal.RequireFromString("100.5"),
+				High:      decimal.RequireFromString("102.25"),
+				Low:       decimal.RequireFromString("99.75"),
+				Close:     decimal.RequireFromString("101.125"),
+				AdjClose:  decimal.RequireFromString("100.875"),
+				Volume:    123456,
+			},
+			{Timestamp: 1700086400, Null: true},
+		},
+	}
+	chart.Meta.Symbol = "AAPL"
+	chart.Meta.DataGranularity = string(dorfyn.Interval1Day)
+
+	symbol, price, at := "AAPL", 101.125, dorfyn.UnixTime(1700000000)
+	quote := dorfyn.Quote{Symbol: &symbol, RegularMarketPrice: &price, RegularMarketTime: &at}
+
+	chain := &dorfyn.OptionChain{
+		Meta: dorfyn.OptionsMeta{UnderlyingSymbol: "AAPL"},
+		Straddles: []dorfyn.Straddle{{
+			Strike: 100,
+			Call:   &dorfyn.Contract{Symbol: "AAPL231117C00100000", Strike: 100, Bid: 2.5, Ask: 2.6, InTheMoney: true},
+			Put:    &dorfyn.Contract{Symbol: "AAPL231117P00100000", Strike: 100, Bid: 1.1, Ask: 1.2},
+		}},
+	}
+	snapshot := time.Unix(1700000000, 0)
+
+	for i := 0; i < 2; i++ {
+		if err := e.ExportChart(chart); err != nil {
+			return err
+		}
+		if err := e.ExportQuotes([]dorfyn.Quote{quote}); err != nil {
+			return err
+		}
+		if err := e.ExportOptionChain(chain, snapshot); err != nil {
+			return err
+		}
+	}
+
+	if err := checkBars(db, chart.Bars); err != nil {
+		return err
+	}
+	if err := checkCount(db, "yf_quotes", 1); err != nil {
+		return err
+	}
+	return checkCount(db, "yf_options", 2)
+}
+
+// checkBars checks that the stored bars match the given ones, with NULL prices for null bars.
+func checkBars(db *sql.DB, bars []dorfyn.ChartBar) error {
+	rows, err := db.Query(`SELECT "time", "open", "high", "low", "close", "adj_close", "volume" FROM "yf_bars"
+		WHERE "symbol" = 'AAPL' AND "bar_interval" = '1d' ORDER BY "time"`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	i := 0
+	for ; rows.Next(); i++ {
+		if i >= len(bars) {
+			return fmt.Errorf("more bars stored than exported")
+		}
+		var ts int64
+		var volume int
+		var prices [5]decimal.NullDecimal
+		if err := rows.Scan(&ts, &prices[0], &prices[1], &prices[2], &prices[3], &prices[4], &volume); err != nil {
+			return err
+		}
+
+		want := bars[i]
+		if dorfyn.UnixTime(ts) != want.Timestamp || volume != want.Volume {
+			return fmt.Errorf("bar %d: got time %d and volume %d, want %d and %d", i, ts, volume, want.Timestamp, want.Volume)
+		}
+		for j, p := range []decimal.Decimal{want.Open, want.High, want.Low, want.Close, want.AdjClose} {
+			if prices[j].Valid == want.Null || !want.Null && !prices[j].Decimal.Equal(p) {
+				return fmt.Errorf("bar %d: got price %v, want %v (null: %v)", i, prices[j], p, want.Null)
+			}
+		}
+	}
+	if i != len(bars) {
+		return fmt.Errorf("got %d bars, want %d", i, len(bars))
+	}
+	return rows.Err()
+}
+
+// checkCount checks that the given table holds the given number of rows.
+func checkCount(db *sql.DB, table string, want int) error {
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM "` + table + `"`).Scan(&n); err != nil {
+		return err
+	}
+	if n != want {
+		return fmt.Errorf("got %d rows in %s, want %d", n, table, want)
+	}
+	return nil
+}
@@ -3,3 +3,5 @@ module github.com/joce/dorfyn
 go 1.20
 
 require github.com/shopspring/decimal v1.3.1
+
+require github.com/gorilla/websocket v1.5.0
@@ -0,0 +1,63 @@
+// Package indicators computes technical indicators over series of dorfyn.ChartBar or decimal values.
+//
+// Each indicator comes in two forms: a streaming type, updated one value or bar at a time so live series don't need
+// to be recomputed from scratch, and a function computing the whole series at once. The values of the functions are
+// aligned with their input, and invalid until the indicator has seen enough values to warm up. Periods must be
+// positive: the constructors and functions return an argument error otherwise.
+package indicators
+
+import (
+	"fmt"
+
+	"github.com/joce/dorfyn"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	one     = decimal.NewFromInt(1)
+	two     = decimal.NewFromInt(2)
+	three   = decimal.NewFromInt(3)
+	fifty   = decimal.NewFromInt(50)
+	hundred = decimal.NewFromInt(100)
+)
+
+// Closes returns the closes of the given bars. Null bars have a zero close: drop or fill them with dorfyn.Clean first.
+func Closes(bars []dorfyn.ChartBar) []decimal.Decimal {
+	closes := make([]decimal.Decimal, len(bars))
+	for i, bar := range bars {
+		closes[i] = bar.Close
+	}
+	return closes
+}
+
+// AdjCloses returns the adjusted closes of the given bars. Null bars have a zero adjusted close: drop or fill them with
+// dorfyn.Clean first.
+func AdjCloses(bars []dorfyn.ChartBar) []decimal.Decimal {
+	closes := make([]decimal.Decimal, len(bars))
+	for i, bar := range bars {
+		closes[i] = bar.AdjClose
+	}
+	return closes
+}
+
+// updater is a streaming indicator of a single decimal value.
+type updater interface {
+	Update(v decimal.Decimal) (decimal.Decimal, bool)
+}
+
+// series feeds the given values to the given streaming indicator and returns its successive values.
+func series(u updater, values []decimal.Decimal) []decimal.NullDecimal {
+	out := make([]decimal.NullDecimal, len(values))
+	for i, v := range values {
+		out[i].Decimal, out[i].Valid = u.Update(v)
+	}
+	return out
+}
+
+// checkPeriod returns an error if the given period of the given indicator isn't positive.
+func checkPeriod(indicator string, period int) error {
+	if period <= 0 {
+		return dorfyn.CreateArgumentError(fmt.Sprintf("Invalid period %d provided to %s", period, indicator))
+	}
+	return nil
+}
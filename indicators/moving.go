@@ -0,0 +1,103 @@
+package indicators
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// SMAStream is a streaming simple moving average.
+type SMAStream struct {
+	period int
+	window []decimal.Decimal
+	next   int
+	sum    decimal.Decimal
+	count  int
+}
+
+// NewSMAStream returns a streaming simple moving average over the given number of values.
+func NewSMAStream(period int) (*SMAStream, error) {
+	if err := checkPeriod("NewSMAStream", period); err != nil {
+		return nil, err
+	}
+	return newSMAStream(period), nil
+}
+
+// newSMAStream returns a streaming simple moving average over the given positive number of values.
+func newSMAStream(period int) *SMAStream {
+	return &SMAStream{period: period, window: make([]decimal.Decimal, period)}
+}
+
+// Update adds a value and returns the average of the last period values. It returns false until period values have
+// been added.
+func (s *SMAStream) Update(v decimal.Decimal) (decimal.Decimal, bool) {
+	s.sum = s.sum.Sub(s.window[s.next]).Add(v)
+	s.window[s.next] = v
+	s.next = (s.next + 1) % s.period
+	if s.count < s.period {
+		s.count++
+	}
+	return s.Value()
+}
+
+// Value returns the current average, and false if fewer than period values have been added.
+func (s *SMAStream) Value() (decimal.Decimal, bool) {
+	if s.count < s.period {
+		return decimal.Zero, false
+	}
+	return s.sum.Div(decimal.NewFromInt(int64(s.period))), true
+}
+
+// SMA returns the simple moving average of the given values over the given period.
+func SMA(values []decimal.Decimal, period int) ([]decimal.NullDecimal, error) {
+	if err := checkPeriod("SMA", period); err != nil {
+		return nil, err
+	}
+	return series(newSMAStream(period), values), nil
+}
+
+// EMAStream is a streaming exponential moving average. It's seeded with the simple average of its first period values.
+type EMAStream struct {
+	alpha decimal.Decimal
+	seed  *SMAStream
+	value decimal.Decimal
+	ready bool
+}
+
+// NewEMAStream returns a streaming exponential moving average over the given period, with a smoothing factor of
+// 2 / (period + 1).
+func NewEMAStream(period int) (*EMAStream, error) {
+	if err := checkPeriod("NewEMAStream", period); err != nil {
+		return nil, err
+	}
+	return newEMAStream(period), nil
+}
+
+// newEMAStream returns a streaming exponential moving average over the given positive period.
+func newEMAStream(period int) *EMAStream {
+	return &EMAStream{
+		alpha: two.Div(decimal.NewFromInt(int64(period + 1))),
+		seed:  newSMAStream(period),
+	}
+}
+
+// Update adds a value and returns the updated average. It returns false until period values have been added.
+func (e *EMAStream) Update(v decimal.Decimal) (decimal.Decimal, bool) {
+	if !e.ready {
+		e.value, e.ready = e.seed.Update(v)
+		return e.value, e.ready
+	}
+	e.value = v.Sub(e.value).Mul(e.alpha).Add(e.value)
+	return e.value, true
+}
+
+// Value returns the current average, and false if fewer than period values have been added.
+func (e *EMAStream) Value() (decimal.Decimal, bool) {
+	return e.value, e.ready
+}
+
+// EMA returns the exponential moving average of the given values over the given period.
+func EMA(values []decimal.Decimal, period int) ([]decimal.NullDecimal, error) {
+	if err := checkPeriod("EMA", period); err != nil {
+		return nil, err
+	}
+	return series(newEMAStream(period), values), nil
+}
@@ -0,0 +1,139 @@
+package indicators
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// RSIStream is a streaming relative strength index, using Wilder's smoothing.
+type RSIStream struct {
+	period   decimal.Decimal
+	seedGain *SMAStream
+	seedLoss *SMAStream
+	avgGain  decimal.Decimal
+	avgLoss  decimal.Decimal
+	previous *decimal.Decimal
+	ready    bool
+}
+
+// NewRSIStream returns a streaming relative strength index over the given period, usually 14.
+func NewRSIStream(period int) (*RSIStream, error) {
+	if err := checkPeriod("NewRSIStream", period); err != nil {
+		return nil, err
+	}
+	return &RSIStream{
+		period:   decimal.NewFromInt(int64(period)),
+		seedGain: newSMAStream(period),
+		seedLoss: newSMAStream(period),
+	}, nil
+}
+
+// Update adds a value and returns the updated index, between 0 and 100. It returns false until period changes, that
+// is period + 1 values, have been added.
+func (r *RSIStream) Update(v decimal.Decimal) (decimal.Decimal, bool) {
+	if r.previous == nil {
+		r.previous = &v
+		return decimal.Zero, false
+	}
+
+	change := v.Sub(*r.previous)
+	r.previous = &v
+	gain, loss := decimal.Max(change, decimal.Zero), decimal.Max(change.Neg(), decimal.Zero)
+
+	if !r.ready {
+		r.avgGain, _ = r.seedGain.Update(gain)
+		r.avgLoss, r.ready = r.seedLoss.Update(loss)
+	} else {
+		r.avgGain = r.avgGain.Mul(r.period.Sub(one)).Add(gain).Div(r.period)
+		r.avgLoss = r.avgLoss.Mul(r.period.Sub(one)).Add(loss).Div(r.period)
+	}
+	return r.Value()
+}
+
+// Value returns the current index, and false if fewer than period + 1 values have been added.
+func (r *RSIStream) Value() (decimal.Decimal, bool) {
+	if !r.ready {
+		return decimal.Zero, false
+	}
+	if r.avgLoss.IsZero() {
+		if r.avgGain.IsZero() {
+			return fifty, true
+		}
+		return hundred, true
+	}
+	rs := r.avgGain.Div(r.avgLoss)
+	return hundred.Sub(hundred.Div(one.Add(rs))), true
+}
+
+// RSI returns the relative strength index of the given values over the given period.
+func RSI(values []decimal.Decimal, period int) ([]decimal.NullDecimal, error) {
+	r, err := NewRSIStream(period)
+	if err != nil {
+		return nil, err
+	}
+	return series(r, values), nil
+}
+
+// MACDValue is a value of the moving average convergence divergence indicator.
+type MACDValue struct {
+	// MACD is the difference between the fast and the slow moving averages.
+	MACD decimal.Decimal
+	// Signal is the moving average of MACD.
+	Signal decimal.Decimal
+	// Histogram is the difference between MACD and Signal.
+	Histogram decimal.Decimal
+	// Valid tells whether all the fields are set. MACD becomes available before Signal and Histogram do.
+	Valid bool
+}
+
+// MACDStream is a streaming moving average convergence divergence indicator.
+type MACDStream struct {
+	fast   *EMAStream
+	slow   *EMAStream
+	signal *EMAStream
+	value  MACDValue
+}
+
+// NewMACDStream returns a streaming moving average convergence divergence indicator with the given fast, slow and
+// signal periods, usually 12, 26 and 9.
+func NewMACDStream(fast int, slow int, signal int) (*MACDStream, error) {
+	for _, period := range []int{fast, slow, signal} {
+		if err := checkPeriod("NewMACDStream", period); err != nil {
+			return nil, err
+		}
+	}
+	return &MACDStream{fast: newEMAStream(fast), slow: newEMAStream(slow), signal: newEMAStream(signal)}, nil
+}
+
+// Update adds a value and returns the updated indicator.
+func (m *MACDStream) Update(v decimal.Decimal) MACDValue {
+	fast, fastReady := m.fast.Update(v)
+	slow, slowReady := m.slow.Update(v)
+	if !fastReady || !slowReady {
+		return m.value
+	}
+
+	m.value.MACD = fast.Sub(slow)
+	m.value.Signal, m.value.Valid = m.signal.Update(m.value.MACD)
+	if m.value.Valid {
+		m.value.Histogram = m.value.MACD.Sub(m.value.Signal)
+	}
+	return m.value
+}
+
+// Value returns the current value of the indicator.
+func (m *MACDStream) Value() MACDValue {
+	return m.value
+}
+
+// MACD returns the moving average convergence divergence of the given values.
+func MACD(values []decimal.Decimal, fast int, slow int, signal int) ([]MACDValue, error) {
+	m, err := NewMACDStream(fast, slow, signal)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MACDValue, len(values))
+	for i, v := range values {
+		out[i] = m.Update(v)
+	}
+	return out, nil
+}
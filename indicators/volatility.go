@@ -0,0 +1,141 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/joce/dorfyn"
+	"github.com/shopspring/decimal"
+)
+
+// BollingerValue is a value of the Bollinger bands.
+type BollingerValue struct {
+	Upper  decimal.Decimal
+	Middle decimal.Decimal
+	Lower  decimal.Decimal
+	// Valid tells whether the fields are set.
+	Valid bool
+}
+
+// BollingerStream is streaming Bollinger bands.
+type BollingerStream struct {
+	width  decimal.Decimal
+	sma    *SMAStream
+	values []decimal.Decimal
+	next   int
+	value  BollingerValue
+}
+
+// NewBollingerStream returns streaming Bollinger bands over the given period, usually 20, with the given width in
+// standard deviations, usually 2.
+func NewBollingerStream(period int, width float64) (*BollingerStream, error) {
+	if err := checkPeriod("NewBollingerStream", period); err != nil {
+		return nil, err
+	}
+	return &BollingerStream{
+		width:  decimal.NewFromFloat(width),
+		sma:    newSMAStream(period),
+		values: make([]decimal.Decimal, period),
+	}, nil
+}
+
+// Update adds a value and returns the updated bands.
+func (b *BollingerStream) Update(v decimal.Decimal) BollingerValue {
+	b.values[b.next] = v
+	b.next = (b.next + 1) % len(b.values)
+
+	mean, ok := b.sma.Update(v)
+	if !ok {
+		return b.value
+	}
+
+	// The population standard deviation of the window. decimal has no square root, which is taken in floating point.
+	variance := decimal.Zero
+	for _, x := range b.values {
+		d := x.Sub(mean)
+		variance = variance.Add(d.Mul(d))
+	}
+	variance = variance.Div(decimal.NewFromInt(int64(len(b.values))))
+	stddev := decimal.NewFromFloat(math.Sqrt(variance.InexactFloat64()))
+
+	b.value = BollingerValue{
+		Upper:  mean.Add(stddev.Mul(b.width)),
+		Middle: mean,
+		Lower:  mean.Sub(stddev.Mul(b.width)),
+		Valid:  true,
+	}
+	return b.value
+}
+
+// Value returns the current bands.
+func (b *BollingerStream) Value() BollingerValue {
+	return b.value
+}
+
+// Bollinger returns the Bollinger bands of the given values.
+func Bollinger(values []decimal.Decimal, period int, width float64) ([]BollingerValue, error) {
+	b, err := NewBollingerStream(period, width)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]BollingerValue, len(values))
+	for i, v := range values {
+		out[i] = b.Update(v)
+	}
+	return out, nil
+}
+
+// ATRStream is a streaming average true range, using Wilder's smoothing.
+type ATRStream struct {
+	period    decimal.Decimal
+	seed      *SMAStream
+	value     decimal.Decimal
+	prevClose *decimal.Decimal
+	ready     bool
+}
+
+// NewATRStream returns a streaming average true range over the given period, usually 14.
+func NewATRStream(period int) (*ATRStream, error) {
+	if err := checkPeriod("NewATRStream", period); err != nil {
+		return nil, err
+	}
+	return &ATRStream{period: decimal.NewFromInt(int64(period)), seed: newSMAStream(period)}, nil
+}
+
+// Update adds a bar and returns the updated average. It returns false until period bars have been added. Null bars
+// are ignored.
+func (a *ATRStream) Update(bar dorfyn.ChartBar) (decimal.Decimal, bool) {
+	if bar.Null {
+		return a.Value()
+	}
+	tr := bar.High.Sub(bar.Low)
+	if a.prevClose != nil {
+		tr = decimal.Max(tr, bar.High.Sub(*a.prevClose).Abs(), bar.Low.Sub(*a.prevClose).Abs())
+	}
+	closing := bar.Close
+	a.prevClose = &closing
+
+	if !a.ready {
+		a.value, a.ready = a.seed.Update(tr)
+		return a.value, a.ready
+	}
+	a.value = a.value.Mul(a.period.Sub(one)).Add(tr).Div(a.period)
+	return a.value, true
+}
+
+// Value returns the current average, and false if fewer than period bars have been added.
+func (a *ATRStream) Value() (decimal.Decimal, bool) {
+	return a.value, a.ready
+}
+
+// ATR returns the average true range of the given bars over the given period.
+func ATR(bars []dorfyn.ChartBar, period int) ([]decimal.NullDecimal, error) {
+	a, err := NewATRStream(period)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]decimal.NullDecimal, len(bars))
+	for i, bar := range bars {
+		out[i].Decimal, out[i].Valid = a.Update(bar)
+	}
+	return out, nil
+}
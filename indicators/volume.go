@@ -0,0 +1,65 @@
+package indicators
+
+import (
+	"time"
+
+	"github.com/joce/dorfyn"
+	"github.com/shopspring/decimal"
+)
+
+// VWAPStream is a streaming volume-weighted average price, using the typical price (high + low + close) / 3 of bars.
+type VWAPStream struct {
+	priceVolume decimal.Decimal
+	volume      decimal.Decimal
+}
+
+// NewVWAPStream returns a streaming volume-weighted average price.
+func NewVWAPStream() *VWAPStream {
+	return &VWAPStream{}
+}
+
+// Update adds a bar and returns the updated average. It returns false while no volume has been added. Null bars are
+// ignored.
+func (w *VWAPStream) Update(bar dorfyn.ChartBar) (decimal.Decimal, bool) {
+	if bar.Null {
+		return w.Value()
+	}
+	volume := decimal.NewFromInt(int64(bar.Volume))
+	typical := bar.High.Add(bar.Low).Add(bar.Close).Div(three)
+	w.priceVolume = w.priceVolume.Add(typical.Mul(volume))
+	w.volume = w.volume.Add(volume)
+	return w.Value()
+}
+
+// Value returns the current average, and false if no volume has been added.
+func (w *VWAPStream) Value() (decimal.Decimal, bool) {
+	if w.volume.IsZero() {
+		return decimal.Zero, false
+	}
+	return w.priceVolume.Div(w.volume), true
+}
+
+// Reset starts a new average, typically at the beginning of a session.
+func (w *VWAPStream) Reset() {
+	*w = VWAPStream{}
+}
+
+// VWAP returns the volume-weighted average price of the given bars, restarting at each new day in the given location,
+// usually the exchange's one. Defaults to UTC if loc is nil.
+func VWAP(bars []dorfyn.ChartBar, loc *time.Location) []decimal.NullDecimal {
+	if loc == nil {
+		loc = time.UTC
+	}
+	w := NewVWAPStream()
+	out := make([]decimal.NullDecimal, len(bars))
+	var day time.Time
+	for i, bar := range bars {
+		t := bar.Timestamp.In(loc)
+		if d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc); !d.Equal(day) {
+			day = d
+			w.Reset()
+		}
+		out[i].Decimal, out[i].Valid = w.Update(bar)
+	}
+	return out
+}
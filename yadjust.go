@@ -0,0 +1,116 @@
+package dorfyn
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// AdjustmentMode selects the corporate actions to adjust historical bars for.
+type AdjustmentMode int
+
+const (
+	// AdjustSplits adjusts prices and volumes for stock splits.
+	AdjustSplits AdjustmentMode = 1 << iota
+	// AdjustDividends adjusts prices for cash dividends.
+	AdjustDividends
+
+	// AdjustAll adjusts for both splits and dividends.
+	AdjustAll = AdjustSplits | AdjustDividends
+)
+
+// AdjustBars returns a copy of the given bars, in chronological order, adjusted backward for the given corporate
+// actions: the prices of the bars preceding an event are scaled so they are comparable with the prices that follow it.
+//
+// For splits, prices are divided and volumes multiplied by the split factor. For dividends, prices are multiplied by
+// 1 - amount / close, where close is the close of the last non-null bar before the ex-date. AdjClose is left untouched.
+//
+// The bars must not already be adjusted for the selected events. Note that the bars returned by GetChart are already
+// split-adjusted, as are the dividend amounts, so only AdjustDividends applies to them. Dividend amounts must be
+// expressed in the same share basis as the bars.
+func AdjustBars(bars []ChartBar, splits []Split, dividends []Dividend, mode AdjustmentMode) []ChartBar {
+	adjusted := make([]ChartBar, len(bars))
+	copy(adjusted, bars)
+	sort.SliceStable(adjusted, func(i, j int) bool { return adjusted[i].Timestamp < adjusted[j].Timestamp })
+
+	if mode&AdjustSplits == 0 {
+		splits = nil
+	}
+	if mode&AdjustDividends == 0 {
+		dividends = nil
+	}
+	splits = append([]Split(nil), splits...)
+	sort.Slice(splits, func(i, j int) bool { return splits[i].Date.Before(splits[j].Date) })
+	dividends = append([]Dividend(nil), dividends...)
+	sort.Slice(dividends, func(i, j int) bool { return dividends[i].Date.Before(dividends[j].Date) })
+
+	one := decimal.NewFromInt(1)
+	splitFactor, dividendFactor := one, one
+	si, di := len(splits)-1, len(dividends)-1
+
+	// Walk the bars backward, accumulating the factors of the events that follow each bar.
+	for i := len(adjusted) - 1; i >= 0; i-- {
+		bar := &adjusted[i]
+		ts := int64(bar.Timestamp)
+
+		for ; si >= 0 && ts < splits[si].Date.Unix(); si-- {
+			splitFactor = splitFactor.Mul(splits[si].Factor())
+		}
+		for ; di >= 0 && ts < dividends[di].Date.Unix(); di-- {
+			// bar is the last one before the ex-date. The reference price of the dividend is its close, or the close of
+			// the last bar before it if it is null.
+			reference := decimal.Zero
+			for j := i; j >= 0 && !reference.IsPositive(); j-- {
+				if !adjusted[j].Null {
+					reference = adjusted[j].Close
+				}
+			}
+			if reference.IsPositive() && dividends[di].Amount.LessThan(reference) {
+				dividendFactor = dividendFactor.Mul(one.Sub(dividends[di].Amount.Div(reference)))
+			} else {
+				logInfo("Ignoring dividend of %v on %v: no usable reference close\n", dividends[di].Amount, dividends[di].Date)
+			}
+		}
+
+		if splitFactor.Equal(one) && dividendFactor.Equal(one) {
+			continue
+		}
+
+		priceFactor := dividendFactor.Div(splitFactor)
+		bar.Open = bar.Open.Mul(priceFactor)
+		bar.Low = bar.Low.Mul(priceFactor)
+		bar.High = bar.High.Mul(priceFactor)
+		bar.Close = bar.Close.Mul(priceFactor)
+		bar.Volume = int(decimal.NewFromInt(int64(bar.Volume)).Mul(splitFactor).Round(0).IntPart())
+	}
+
+	return adjusted
+}
+
+// AdjustToAdjClose returns a copy of the given bars with their open, low, high and close scaled by the ratio between
+// AdjClose and Close, the way Yahoo! finance adjusts closes for dividends. Bars without a close are left untouched.
+func AdjustToAdjClose(bars []ChartBar) []ChartBar {
+	adjusted := make([]ChartBar, len(bars))
+	copy(adjusted, bars)
+
+	for i := range adjusted {
+		bar := &adjusted[i]
+		if !bar.Close.IsPositive() || bar.AdjClose.Equal(bar.Close) {
+			continue
+		}
+
+		ratio := bar.AdjClose.Div(bar.Close)
+		bar.Open = bar.Open.Mul(ratio)
+		bar.Low = bar.Low.Mul(ratio)
+		bar.High = bar.High.Mul(ratio)
+		bar.Close = bar.AdjClose
+	}
+
+	return adjusted
+}
+
+// Adjusted returns the bars of the chart adjusted for the dividends of the chart. As Yahoo! finance charts are already
+// split-adjusted, the AdjustSplits bit of mode is ignored.
+func (c *Chart) Adjusted(mode AdjustmentMode) []ChartBar {
+	return AdjustBars(c.Bars, nil, c.Dividends, mode&^AdjustSplits)
+}
@@ -0,0 +1,587 @@
+package dorfyn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// alertMetrics maps the metric keywords of the alert rules to the quote fields they read.
+var alertMetrics = map[string]string{
+	"price":  "RegularMarketPrice",
+	"volume": "RegularMarketVolume",
+	"bid":    "Bid",
+	"ask":    "Ask",
+	"change": "RegularMarketChangePercent",
+}
+
+// alertCondition is the kind of condition of an alert rule.
+type alertCondition int
+
+const (
+	// alertAbove fires when the metric is above the threshold.
+	alertAbove alertCondition = iota
+	// alertBelow fires when the metric is below the threshold.
+	alertBelow
+	// alertMoves fires when the metric moved by the threshold percentage, either way, within the window.
+	alertMoves
+	// alertRises fires when the metric rose by the threshold percentage within the window.
+	alertRises
+	// alertFalls fires when the metric fell by the threshold percentage within the window.
+	alertFalls
+)
+
+// AlertRule is a condition on the quotes of a security, parsed from an expression such as:
+//
+//	AAPL crosses above 200
+//	AAPL price >= 180
+//	BTC-USD moves 5% in 1h
+//	ETH-USD falls 3% in 30m
+//	volume > 2x AverageDailyVolume10Day
+//
+// An expression is an optional symbol, an optional metric, and a condition. Without a symbol, the rule applies to
+// every quote evaluated. The metric is price (the default), volume, bid, ask, change (the regular market change
+// percent) or the name of any numeric Quote field. The condition is either a comparison (>, >=, <, <=) with a number,
+// optionally multiplied by a quote field ("2x AverageDailyVolume10Day"); a crossing ("crosses above 200"), which only
+// fires once the metric was seen on the other side of the threshold; or a percentage move within a time window
+// ("moves", "rises" or "falls", with windows such as 90s, 30m, 1h or 1d).
+type AlertRule struct {
+	// Name identifies the rule in alerts. Defaults to its expression.
+	Name string
+	// Expression is the expression the rule was parsed from.
+	Expression string
+	// Symbol is the symbol the rule applies to, or empty if it applies to every symbol.
+	Symbol string
+	// Cooldown is the minimum delay between two alerts of the rule for a given symbol.
+	Cooldown time.Duration
+	// Hysteresis is the fraction of the threshold by which the metric must come back from it before the rule can fire
+	// again. With no hysteresis, the rule can fire again as soon as its condition stops being met.
+	Hysteresis float64
+
+	metric     string
+	condition  alertCondition
+	threshold  float64
+	multiplier string
+	crossing   bool
+	inclusive  bool
+	window     time.Duration
+}
+
+// ParseAlertRule parses an alert rule expression. See AlertRule for the syntax.
+func ParseAlertRule(expression string) (*AlertRule, error) {
+	r := &AlertRule{Name: expression, Expression: expression, metric: alertMetrics["price"]}
+	tokens := tokenizeAlertRule(expression)
+	fail := func(reason string) (*AlertRule, error) {
+		return nil, CreateArgumentError(fmt.Sprintf("Invalid alert rule %q: %s", expression, reason))
+	}
+
+	if len(tokens) > 0 && !isAlertKeyword(tokens[0]) && quoteNumberField(tokens[0]) == "" {
+		r.Symbol = strings.ToUpper(tokens[0])
+		tokens = tokens[1:]
+	}
+	if len(tokens) > 0 && !isAlertKeyword(tokens[0]) {
+		field := quoteNumberField(tokens[0])
+		if field == "" {
+			return fail("unknown metric " + tokens[0])
+		}
+		r.metric = field
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return fail("missing condition")
+	}
+
+	var err error
+	switch verb := strings.ToLower(tokens[0]); verb {
+	case ">", ">=", "<", "<=":
+		r.condition, r.inclusive = alertAbove, strings.HasSuffix(verb, "=")
+		if verb[0] == '<' {
+			r.condition = alertBelow
+		}
+		if len(tokens) < 2 {
+			return fail("missing threshold")
+		}
+		if r.threshold, err = strconv.ParseFloat(tokens[1], 64); err != nil {
+			return fail("invalid threshold " + tokens[1])
+		}
+		switch len(tokens) {
+		case 2:
+		case 4:
+			if m := strings.ToLower(tokens[2]); m != "x" && m != "×" && m != "*" {
+				return fail("expected a multiplier, got " + tokens[2])
+			}
+			if r.multiplier = quoteNumberField(tokens[3]); r.multiplier == "" {
+				return fail("unknown quote field " + tokens[3])
+			}
+		default:
+			return fail("unexpected tokens after threshold")
+		}
+
+	case "crosses":
+		if len(tokens) != 3 {
+			return fail("expected crosses above|below <threshold>")
+		}
+		switch strings.ToLower(tokens[1]) {
+		case "above":
+			r.condition = alertAbove
+		case "below":
+			r.condition = alertBelow
+		default:
+			return fail("expected above or below, got " + tokens[1])
+		}
+		if r.threshold, err = strconv.ParseFloat(tokens[2], 64); err != nil {
+			return fail("invalid threshold " + tokens[2])
+		}
+		r.crossing = true
+
+	case "moves", "rises", "falls":
+		if len(tokens) != 5 || tokens[2] != "%" || strings.ToLower(tokens[3]) != "in" {
+			return fail("expected " + verb + " <percent>% in <window>")
+		}
+		r.condition = map[string]alertCondition{"moves": alertMoves, "rises": alertRises, "falls": alertFalls}[verb]
+		r.inclusive = true
+		if r.threshold, err = strconv.ParseFloat(tokens[1], 64); err != nil || r.threshold <= 0 {
+			return fail("invalid percentage " + tokens[1])
+		}
+		if r.window, err = parseAlertWindow(tokens[4]); err != nil {
+			return fail("invalid window " + tokens[4])
+		}
+
+	default:
+		return fail("unknown condition " + tokens[0])
+	}
+
+	return r, nil
+}
+
+// MustParseAlertRule is like ParseAlertRule but panics if the expression can't be parsed.
+func MustParseAlertRule(expression string) *AlertRule {
+	r, err := ParseAlertRule(expression)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// tokenizeAlertRule splits an alert rule expression into tokens, separating operators, percent signs and multipliers
+// from the numbers they are attached to.
+func tokenizeAlertRule(expression string) []string {
+	var tokens []string
+	for _, word := range strings.Fields(expression) {
+		for word != "" {
+			switch {
+			case strings.HasPrefix(word, ">=") || strings.HasPrefix(word, "<="):
+				tokens, word = append(tokens, word[:2]), word[2:]
+			case word[0] == '>' || word[0] == '<' || word[0] == '%' || word[0] == '*':
+				tokens, word = append(tokens, word[:1]), word[1:]
+			case strings.HasPrefix(word, "×"):
+				tokens, word = append(tokens, "×"), word[len("×"):]
+			default:
+				if n := numberPrefix(word); n > 0 && n < len(word) && (word[n] == 'x' || word[n] == 'X') {
+					tokens, word = append(tokens, word[:n], "x"), word[n+1:]
+					continue
+				}
+				end := strings.IndexAny(word, "%×*<>")
+				if end < 0 {
+					end = len(word)
+				}
+				tokens, word = append(tokens, word[:end]), word[end:]
+			}
+		}
+	}
+	return tokens
+}
+
+// numberPrefix returns the length of the decimal number at the start of s.
+func numberPrefix(s string) int {
+	n := 0
+	for n < len(s) && (s[n] >= '0' && s[n] <= '9' || s[n] == '.') {
+		n++
+	}
+	return n
+}
+
+// isAlertKeyword returns whether the given token starts the condition of an alert rule.
+func isAlertKeyword(token string) bool {
+	switch strings.ToLower(token) {
+	case ">", ">=", "<", "<=", "crosses", "moves", "rises", "falls":
+		return true
+	}
+	return false
+}
+
+// parseAlertWindow parses a time window, accepting day units on top of the ones of time.ParseDuration.
+func parseAlertWindow(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n float64
+		n, err = strconv.ParseFloat(days, 64)
+		d = time.Duration(n * float64(24*time.Hour))
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("non-positive window %s", s)
+	}
+	return d, err
+}
+
+// quoteNumberField returns the name of the numeric Quote field designated by the given metric keyword, Go field name
+// or JSON field name, all case-insensitive, or an empty string if there is none.
+func quoteNumberField(name string) string {
+	if field, ok := alertMetrics[strings.ToLower(name)]; ok {
+		return field
+	}
+	t := reflect.TypeOf(Quote{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !strings.EqualFold(f.Name, name) && !strings.EqualFold(strings.Split(f.Tag.Get("json"), ",")[0], name) {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Float64 || ft.Kind() == reflect.Int {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// quoteNumber returns the value of the given numeric Quote field, and false if it is absent from the quote.
+func quoteNumber(q *Quote, field string) (float64, bool) {
+	v := reflect.ValueOf(q).Elem().FieldByName(field)
+	if !v.IsValid() {
+		return 0, false
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Float64:
+		return v.Float(), true
+	case reflect.Int:
+		return float64(v.Int()), true
+	}
+	return 0, false
+}
+
+// Alert is the notification of a rule whose condition was met.
+type Alert struct {
+	Rule   *AlertRule
+	Symbol string
+	// Value is the value of the metric that triggered the alert: the metric itself, or the move percentage for move
+	// rules.
+	Value float64
+	// Threshold is the threshold the value was compared to, after applying any multiplier.
+	Threshold float64
+	// Time is the time of the quote that triggered the alert.
+	Time  time.Time
+	Quote Quote
+}
+
+// String returns a human readable description of the alert.
+func (a Alert) String() string {
+	return fmt.Sprintf("%s: %s (value %g, threshold %g) at %s", a.Symbol, a.Rule.Name, a.Value, a.Threshold,
+		a.Time.Format(time.RFC3339))
+}
+
+// MarshalJSON encodes the alert in the form sent by WebhookNotifier.
+func (a Alert) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Rule       string    `json:"rule"`
+		Expression string    `json:"expression"`
+		Symbol     string    `json:"symbol"`
+		Value      float64   `json:"value"`
+		Threshold  float64   `json:"threshold"`
+		Time       time.Time `json:"time"`
+		Message    string    `json:"message"`
+		Quote      Quote     `json:"quote"`
+	}{a.Rule.Name, a.Rule.Expression, a.Symbol, a.Value, a.Threshold, a.Time, a.String(), a.Quote})
+}
+
+// Notifier delivers alerts.
+type Notifier interface {
+	Notify(alert Alert) error
+}
+
+// NotifierFunc is a function used as a Notifier.
+type NotifierFunc func(alert Alert) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(alert Alert) error {
+	return f(alert)
+}
+
+// LogNotifier writes alerts to a logger.
+type LogNotifier struct {
+	// Logger is the logger the alerts are written to. Defaults to the library's Logger.
+	Logger *log.Logger
+}
+
+// Notify writes the alert to the logger.
+func (n LogNotifier) Notify(alert Alert) error {
+	logger := n.Logger
+	if logger == nil {
+		logger = Logger
+	}
+	logger.Printf("[alert] %s", alert)
+	return nil
+}
+
+// WebhookNotifier posts alerts as JSON to a URL.
+type WebhookNotifier struct {
+	URL string
+	// Header holds additional headers sent with each request, such as authorization.
+	Header http.Header
+	// Client is the HTTP client used to post the alerts. Defaults to the library's HTTP client.
+	Client *http.Client
+}
+
+// Notify posts the alert to the webhook.
+func (n WebhookNotifier) Notify(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range n.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	c := n.Client
+	if c == nil {
+		c = httpClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", n.URL, resp.Status)
+	}
+	return nil
+}
+
+// alertSample is a metric value at a point in time.
+type alertSample struct {
+	time  time.Time
+	value float64
+}
+
+// alertState is the state of a rule for a given symbol.
+type alertState struct {
+	armed   bool
+	fired   time.Time
+	samples []alertSample
+}
+
+// AlertEngine evaluates alert rules against quotes, and sends the alerts of the rules whose conditions are met to
+// its notifiers.
+type AlertEngine struct {
+	mu        sync.Mutex
+	rules     []*AlertRule
+	states    map[*AlertRule]map[string]*alertState
+	notifiers []Notifier
+}
+
+// NewAlertEngine returns an alert engine sending its alerts to the given notifiers.
+func NewAlertEngine(notifiers ...Notifier) *AlertEngine {
+	return &AlertEngine{states: map[*AlertRule]map[string]*alertState{}, notifiers: notifiers}
+}
+
+// AddNotifier adds a notifier to the engine.
+func (e *AlertEngine) AddNotifier(n Notifier) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.notifiers = append(e.notifiers, n)
+}
+
+// AddRule adds a rule to the engine.
+func (e *AlertEngine) AddRule(r *AlertRule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = append(e.rules, r)
+	e.states[r] = map[string]*alertState{}
+}
+
+// Add parses the given expressions and adds the resulting rules to the engine, with the given cooldown and
+// hysteresis.
+func (e *AlertEngine) Add(cooldown time.Duration, hysteresis float64, expressions ...string) error {
+	rules := make([]*AlertRule, len(expressions))
+	for i, expression := range expressions {
+		r, err := ParseAlertRule(expression)
+		if err != nil {
+			return err
+		}
+		r.Cooldown, r.Hysteresis = cooldown, hysteresis
+		rules[i] = r
+	}
+	for _, r := range rules {
+		e.AddRule(r)
+	}
+	return nil
+}
+
+// RemoveRule removes a rule from the engine.
+func (e *AlertEngine) RemoveRule(r *AlertRule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, rule := range e.rules {
+		if rule == r {
+			e.rules = append(e.rules[:i], e.rules[i+1:]...)
+			break
+		}
+	}
+	delete(e.states, r)
+}
+
+// Evaluate evaluates the rules against the given quote, timed by its RegularMarketTime, or the current time if it
+// has none. See EvaluateAt.
+func (e *AlertEngine) Evaluate(q Quote) []Alert {
+	t := time.Now()
+	if q.RegularMarketTime != nil {
+		t = q.RegularMarketTime.Time()
+	}
+	return e.EvaluateAt(q, t)
+}
+
+// EvaluateAt evaluates the rules against the given quote, received at the given time, sends the resulting alerts to
+// the notifiers, and returns them. Notifiers are called sequentially, and their errors are logged.
+func (e *AlertEngine) EvaluateAt(q Quote, t time.Time) []Alert {
+	if q.Symbol == nil {
+		return nil
+	}
+	symbol := strings.ToUpper(*q.Symbol)
+
+	var alerts []Alert
+	e.mu.Lock()
+	for _, r := range e.rules {
+		if r.Symbol != "" && r.Symbol != symbol {
+			continue
+		}
+		state := e.states[r][symbol]
+		if state == nil {
+			state = &alertState{armed: !r.crossing}
+			e.states[r][symbol] = state
+		}
+		if value, threshold, ok := r.evaluate(state, &q, t); ok {
+			alerts = append(alerts, Alert{Rule: r, Symbol: symbol, Value: value, Threshold: threshold, Time: t, Quote: q})
+		}
+	}
+	notifiers := append([]Notifier{}, e.notifiers...)
+	e.mu.Unlock()
+
+	for _, a := range alerts {
+		for _, n := range notifiers {
+			if err := n.Notify(a); err != nil {
+				logError("Alert notification failed for %s: %v\n", a.Rule.Name, err)
+			}
+		}
+	}
+	return alerts
+}
+
+// Watch evaluates the rules against every quote received by the given watcher.
+func (e *AlertEngine) Watch(w *Watcher) {
+	w.OnUpdate(func(u QuoteUpdate) {
+		e.EvaluateAt(u.Quote, u.Time)
+	})
+}
+
+// evaluate updates the state of the rule with the given quote, and returns the metric value and threshold if the rule
+// fires.
+func (r *AlertRule) evaluate(state *alertState, q *Quote, t time.Time) (float64, float64, bool) {
+	value, ok := quoteNumber(q, r.metric)
+	if !ok {
+		return 0, 0, false
+	}
+
+	threshold := r.threshold
+	if r.multiplier != "" {
+		m, ok := quoteNumber(q, r.multiplier)
+		if !ok {
+			return 0, 0, false
+		}
+		threshold *= m
+	}
+
+	above := r.condition != alertBelow
+	if r.window > 0 {
+		if value, ok = r.move(state, value, t); !ok {
+			return 0, 0, false
+		}
+	}
+
+	band := threshold * r.Hysteresis
+	if band < 0 {
+		band = -band
+	}
+	var met, rearm bool
+	if above {
+		met = value > threshold || r.inclusive && value == threshold
+		rearm = value < threshold-band || band == 0 && !met
+	} else {
+		met = value < threshold || r.inclusive && value == threshold
+		rearm = value > threshold+band || band == 0 && !met
+	}
+
+	if !state.armed {
+		state.armed = rearm
+		return 0, 0, false
+	}
+	if !met || (!state.fired.IsZero() && t.Sub(state.fired) < r.Cooldown) {
+		return 0, 0, false
+	}
+
+	state.armed, state.fired = false, t
+	return value, threshold, true
+}
+
+// move records the given metric value and returns its percentage move over the rule's window, as compared with the
+// threshold: absolute for moves, signed for rises and negated for falls. Returns false until the window is covered.
+func (r *AlertRule) move(state *alertState, value float64, t time.Time) (float64, bool) {
+	state.samples = append(state.samples, alertSample{t, value})
+
+	// Keep the latest sample at or before the start of the window as reference, and drop the older ones.
+	start := t.Add(-r.window)
+	first := 0
+	for first+1 < len(state.samples) && !state.samples[first+1].time.After(start) {
+		first++
+	}
+	state.samples = state.samples[first:]
+
+	ref := state.samples[0]
+	if ref.time.After(start) || ref.value == 0 {
+		return 0, false
+	}
+
+	pct := (value - ref.value) / ref.value * 100
+	switch r.condition {
+	case alertMoves:
+		if pct < 0 {
+			pct = -pct
+		}
+	case alertFalls:
+		pct = -pct
+	}
+	return pct, true
+}
@@ -0,0 +1,420 @@
+package dorfyn
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEndpoint is a kind of data cached with its own time to live.
+type CacheEndpoint string
+
+const (
+	// CacheQuotes is the data of the quote API.
+	CacheQuotes CacheEndpoint = "quotes"
+	// CacheChart is the data of the chart API for ranges that include the current day.
+	CacheChart CacheEndpoint = "chart"
+	// CacheHistory is the data of the chart API for ranges that ended before the current day.
+	CacheHistory CacheEndpoint = "history"
+	// CacheOptions is the data of the options API.
+	CacheOptions CacheEndpoint = "options"
+	// CacheSummary is the data of the quote summary API.
+	CacheSummary CacheEndpoint = "summary"
+	// CacheFinancials is the data of the fundamentals time series API.
+	CacheFinancials CacheEndpoint = "financials"
+	// CacheSearch is the data of the search API.
+	CacheSearch CacheEndpoint = "search"
+)
+
+const (
+	// CacheForever is the time to live of entries that never expire.
+	CacheForever time.Duration = -1
+	// defaultCacheEntries is the default capacity of the in-memory cache.
+	defaultCacheEntries = 1024
+	// cacheNowTolerance is how close to the current time a range end must be to be considered open ended.
+	cacheNowTolerance = time.Minute
+)
+
+var (
+	// DefaultCacheTTLs are the times to live used for the endpoints with no time to live in CacheParams.TTLs.
+	DefaultCacheTTLs = map[CacheEndpoint]time.Duration{
+		CacheQuotes:     5 * time.Second,
+		CacheChart:      time.Minute,
+		CacheHistory:    CacheForever,
+		CacheOptions:    time.Minute,
+		CacheSummary:    time.Hour,
+		CacheFinancials: 24 * time.Hour,
+		CacheSearch:     10 * time.Minute,
+	}
+
+	// cache is the active response cache, or nil if caching is disabled.
+	cache   *responseCache
+	cacheMu sync.RWMutex
+)
+
+// CacheParams are the parameters of the response cache.
+type CacheParams struct {
+	// MaxEntries is the capacity of the in-memory cache; the least recently used entries are evicted first. Defaults
+	// to 1024.
+	MaxEntries int
+	// Dir is the directory in which the entries are persisted, so they survive restarts. Entries are only kept in
+	// memory if empty. The directory isn't bounded by MaxEntries: expired entries are swept from it when the cache is
+	// enabled, but entries cached forever stay until ClearCache is called.
+	Dir string
+	// TTLs overrides the times to live of DefaultCacheTTLs. A zero time to live disables caching for the endpoint, and
+	// CacheForever keeps its entries until evicted.
+	TTLs map[CacheEndpoint]time.Duration
+	// StaleWhileRevalidate is how long after their expiry entries are still served, while being refreshed in the
+	// background.
+	StaleWhileRevalidate time.Duration
+}
+
+// EnableCache enables caching of the API responses, replacing any cache already enabled. Concurrent identical
+// requests are coalesced into a single call to Yahoo! finance.
+func EnableCache(params *CacheParams) error {
+	c := &responseCache{
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+		calls:   map[string]*cacheCall{},
+		ttls:    map[CacheEndpoint]time.Duration{},
+	}
+	if params != nil {
+		c.params = *params
+	}
+	if c.params.MaxEntries <= 0 {
+		c.params.MaxEntries = defaultCacheEntries
+	}
+	for endpoint, ttl := range DefaultCacheTTLs {
+		c.ttls[endpoint] = ttl
+	}
+	for endpoint, ttl := range c.params.TTLs {
+		c.ttls[endpoint] = ttl
+	}
+	if c.params.Dir != "" {
+		if err := os.MkdirAll(c.params.Dir, 0o755); err != nil {
+			logError("Can't create cache directory: %v\n", err)
+			return err
+		}
+		go c.sweep()
+	}
+
+	cacheMu.Lock()
+	cache = c
+	cacheMu.Unlock()
+	return nil
+}
+
+// DisableCache disables caching of the API responses. Entries persisted on disk are kept.
+func DisableCache() {
+	cacheMu.Lock()
+	cache = nil
+	cacheMu.Unlock()
+}
+
+// ClearCache removes all the entries of the active cache, in memory and on disk.
+func ClearCache() error {
+	c := activeCache()
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+	c.mu.Unlock()
+
+	if c.params.Dir == "" {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(c.params.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// activeCache returns the active response cache, or nil if caching is disabled.
+func activeCache() *responseCache {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+	return cache
+}
+
+// cacheEntry is a cached response.
+type cacheEntry struct {
+	Key  string          `json:"key"`
+	Body json.RawMessage `json:"body"`
+	// Expires is the time at which the entry expires, or zero if it never does.
+	Expires time.Time `json:"expires"`
+}
+
+// fresh returns whether the entry has not expired at the given time.
+func (e *cacheEntry) fresh(now time.Time) bool {
+	return e.Expires.IsZero() || now.Before(e.Expires)
+}
+
+// cacheCall is a fetch in flight, shared by the requests for the same key.
+type cacheCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// responseCache is a cache of API responses, with an in-memory LRU and an optional on-disk store.
+type responseCache struct {
+	params CacheParams
+	ttls   map[CacheEndpoint]time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	calls   map[string]*cacheCall
+}
+
+// get returns the response for the given path and parameters, from the cache if possible, or using fetch otherwise.
+func (c *responseCache) get(path string, params queryParams, fetch func() ([]byte, error)) ([]byte, error) {
+	ttl := c.ttls[cacheEndpointOf(path, params)]
+	if ttl == 0 {
+		return fetch()
+	}
+	key := cacheKey(path, params)
+	now := time.Now()
+
+	if e := c.lookup(key); e != nil {
+		if e.fresh(now) {
+			logDebug("Cache hit for %s\n", key)
+			return e.Body, nil
+		}
+		if now.Before(e.Expires.Add(c.params.StaleWhileRevalidate)) {
+			logDebug("Serving stale cache entry for %s\n", key)
+			go func() {
+				if _, err := c.fetch(key, ttl, fetch); err != nil {
+					logError("Can't refresh stale cache entry for %s: %v\n", key, err)
+				}
+			}()
+			return e.Body, nil
+		}
+	}
+
+	return c.fetch(key, ttl, fetch)
+}
+
+// fetch calls fetch and stores its result under the given key, unless a fetch for that key is already in flight, in
+// which case it waits for its result instead.
+func (c *responseCache) fetch(key string, ttl time.Duration, fetch func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.body, call.err
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	call.body, call.err = fetch()
+	if call.err == nil {
+		e := &cacheEntry{Key: key, Body: call.body}
+		if ttl != CacheForever {
+			e.Expires = time.Now().Add(ttl)
+		}
+		c.store(e)
+	}
+
+	c.mu.Lock()
+	delete(c.calls, key)
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.body, call.err
+}
+
+// lookup returns the entry for the given key, from memory or disk, or nil if there is none.
+func (c *responseCache) lookup(key string) *cacheEntry {
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(el)
+		c.mu.Unlock()
+		return el.Value.(*cacheEntry)
+	}
+	c.mu.Unlock()
+
+	if c.params.Dir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.file(key))
+	if err != nil {
+		return nil
+	}
+	e := &cacheEntry{}
+	if err := json.Unmarshal(data, e); err != nil || e.Key != key {
+		logError("Ignoring invalid cache file for %s: %v\n", key, err)
+		return nil
+	}
+	c.remember(e)
+	return e
+}
+
+// store stores the given entry, in memory and on disk.
+func (c *responseCache) store(e *cacheEntry) {
+	c.remember(e)
+
+	if c.params.Dir == "" {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err == nil {
+		// Write to a temporary file first, so concurrent readers never see a partial entry.
+		tmp := c.file(e.Key) + ".tmp" + strconv.FormatInt(time.Now().UnixNano(), 36)
+		if err = os.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, c.file(e.Key))
+		}
+	}
+	if err != nil {
+		logError("Can't persist cache entry for %s: %v\n", e.Key, err)
+	}
+}
+
+// remember stores the given entry in memory, evicting the least recently used entries beyond capacity.
+func (c *responseCache) remember(e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[e.Key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[e.Key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.params.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).Key)
+	}
+}
+
+// sweep removes from the cache directory the entries that can no longer be served, even stale, and the temporary
+// files left behind by interrupted writes.
+func (c *responseCache) sweep() {
+	files, err := filepath.Glob(filepath.Join(c.params.Dir, "*.json*"))
+	if err != nil {
+		logError("Can't sweep cache directory: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	removed := 0
+	for _, f := range files {
+		if !c.sweepable(f, now) {
+			continue
+		}
+		if err := os.Remove(f); err == nil {
+			removed++
+		}
+	}
+	if removed > 0 {
+		logInfo("Swept %d files from the cache directory\n", removed)
+	}
+}
+
+// sweepable returns whether the given file of the cache directory can be removed at the given time.
+func (c *responseCache) sweepable(file string, now time.Time) bool {
+	if !strings.HasSuffix(file, ".json") {
+		// A temporary file, which may still be being written if recent.
+		info, err := os.Stat(file)
+		return err == nil && now.Sub(info.ModTime()) > time.Hour
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	e := &cacheEntry{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return true
+	}
+	return !e.Expires.IsZero() && !now.Before(e.Expires.Add(c.params.StaleWhileRevalidate))
+}
+
+// file returns the path of the file persisting the entry for the given key.
+func (c *responseCache) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.params.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cacheEndpointOf returns the kind of data requested with the given path and parameters.
+func cacheEndpointOf(path string, params queryParams) CacheEndpoint {
+	switch {
+	case strings.HasPrefix(path, yFinChartAPI):
+		if end, err := strconv.ParseInt(params["period2"], 10, 64); err == nil {
+			today := time.Now().UTC().Truncate(24 * time.Hour)
+			// Exchanges west of UTC may still be trading the previous UTC day, so give them a day of margin.
+			if time.Unix(end, 0).Before(today.AddDate(0, 0, -1)) {
+				return CacheHistory
+			}
+		}
+		return CacheChart
+	case strings.HasPrefix(path, yFinQuoteSummaryAPI):
+		return CacheSummary
+	case strings.HasPrefix(path, yFinOptionsAPI):
+		return CacheOptions
+	case strings.HasPrefix(path, yFinTimeseriesAPI):
+		return CacheFinancials
+	case strings.HasPrefix(path, yFinSearchAPI):
+		return CacheSearch
+	case strings.HasPrefix(path, yFinQuoteAPI):
+		return CacheQuotes
+	}
+	return ""
+}
+
+// cacheKey returns the key of the response for the given path and parameters. Parameters are sorted, symbol lists
+// are upper-cased, and range ends close to the current time, which GetChart uses for open ended ranges, are
+// normalized, so that equivalent requests share their entries. Symbol lists keep their order, which is the order of
+// the quotes returned.
+func cacheKey(path string, params queryParams) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "crumb" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(path)
+	for i, name := range names {
+		value := params[name]
+		switch name {
+		case "symbols":
+			value = strings.ToUpper(value)
+		case "period2":
+			if end, err := strconv.ParseInt(value, 10, 64); err == nil &&
+				time.Since(time.Unix(end, 0)).Abs() < cacheNowTolerance {
+				value = "now"
+			}
+		}
+
+		if i == 0 {
+			b.WriteByte('?')
+		} else {
+			b.WriteByte('&')
+		}
+		b.WriteString(name + "=" + value)
+	}
+	return b.String()
+}
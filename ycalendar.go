@@ -0,0 +1,480 @@
+package dorfyn
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clock is a time of day, in minutes since midnight.
+type clock int
+
+// calendarDay is a day on which an exchange is closed or closes early.
+type calendarDay struct {
+	date  time.Time
+	name  string
+	early bool
+}
+
+// ExchangeCalendar is the trading calendar of an exchange: its sessions, holidays and early closes. Holidays are
+// computed from rules, so calendars work offline for any year, but one-off closures are only known up to the release
+// of the library.
+//
+// Calendars rely on the time zone database of the system. On systems without one, such as Windows or minimal
+// containers, import time/tzdata in your main package or build with -tags timetzdata to embed it; otherwise the
+// exchanges' times fall back to UTC.
+type ExchangeCalendar struct {
+	// Code is the usual code of the exchange, e.g. "NYSE".
+	Code string
+	// Name is the full name of the exchange.
+	Name string
+	// TimeZone is the IANA name of the exchange's time zone.
+	TimeZone string
+
+	preOpen    clock
+	open       clock
+	close      clock
+	postClose  clock
+	earlyClose clock
+	// holidays returns the holidays and early closes of the given year.
+	holidays func(year int) []calendarDay
+
+	mu    sync.Mutex
+	years map[int]map[string]calendarDay
+}
+
+// TradingSession is a trading day of an exchange. The pre-market session runs from PreStart to RegularStart, the
+// regular one from RegularStart to RegularEnd and the post-market one from RegularEnd to PostEnd. Exchanges without
+// extended hours have PreStart equal to RegularStart and PostEnd equal to RegularEnd.
+type TradingSession struct {
+	// Date is the midnight starting the trading day, in the exchange's time zone.
+	Date         time.Time
+	PreStart     time.Time
+	RegularStart time.Time
+	RegularEnd   time.Time
+	PostEnd      time.Time
+	// EarlyClose tells whether the regular session closes earlier than usual.
+	EarlyClose bool
+	// EarlyCloseReason is the name of the holiday causing an early close.
+	EarlyCloseReason string
+}
+
+var (
+	// CalendarNYSE is the trading calendar of the New York Stock Exchange.
+	CalendarNYSE = &ExchangeCalendar{
+		Code: "NYSE", Name: "New York Stock Exchange", TimeZone: "America/New_York",
+		preOpen: 4 * 60, open: 9*60 + 30, close: 16 * 60, postClose: 20 * 60, earlyClose: 13 * 60,
+		holidays: usHolidays,
+	}
+	// CalendarNasdaq is the trading calendar of the Nasdaq Stock Market. It shares its holidays with the NYSE.
+	CalendarNasdaq = &ExchangeCalendar{
+		Code: "NASDAQ", Name: "Nasdaq Stock Market", TimeZone: "America/New_York",
+		preOpen: 4 * 60, open: 9*60 + 30, close: 16 * 60, postClose: 20 * 60, earlyClose: 13 * 60,
+		holidays: usHolidays,
+	}
+	// CalendarTSX is the trading calendar of the Toronto Stock Exchange.
+	CalendarTSX = &ExchangeCalendar{
+		Code: "TSX", Name: "Toronto Stock Exchange", TimeZone: "America/Toronto",
+		preOpen: 9*60 + 30, open: 9*60 + 30, close: 16 * 60, postClose: 16 * 60, earlyClose: 13 * 60,
+		holidays: canadaHolidays,
+	}
+	// CalendarLSE is the trading calendar of the London Stock Exchange.
+	CalendarLSE = &ExchangeCalendar{
+		Code: "LSE", Name: "London Stock Exchange", TimeZone: "Europe/London",
+		preOpen: 8 * 60, open: 8 * 60, close: 16*60 + 30, postClose: 16*60 + 30, earlyClose: 12*60 + 30,
+		holidays: ukHolidays,
+	}
+	// CalendarXETRA is the trading calendar of the XETRA trading venue of the Frankfurt Stock Exchange.
+	CalendarXETRA = &ExchangeCalendar{
+		Code: "XETRA", Name: "Deutsche Börse XETRA", TimeZone: "Europe/Berlin",
+		preOpen: 9 * 60, open: 9 * 60, close: 17*60 + 30, postClose: 17*60 + 30, earlyClose: 14 * 60,
+		holidays: germanyHolidays,
+	}
+
+	// exchangeCalendars maps Yahoo! finance exchange codes, as found in Quote.Exchange and ChartMeta.ExchangeName,
+	// to their trading calendar.
+	exchangeCalendars = map[string]*ExchangeCalendar{
+		"NYQ": CalendarNYSE,
+		"ASE": CalendarNYSE,
+		"PCX": CalendarNYSE,
+		"BTS": CalendarNYSE,
+		"NMS": CalendarNasdaq,
+		"NGM": CalendarNasdaq,
+		"NCM": CalendarNasdaq,
+		"NAS": CalendarNasdaq,
+		"TOR": CalendarTSX,
+		"LSE": CalendarLSE,
+		"GER": CalendarXETRA,
+	}
+
+	// usClosures are the unscheduled closures of the US markets.
+	usClosures = map[string]string{
+		"2001-09-11": "September 11 attacks",
+		"2001-09-12": "September 11 attacks",
+		"2001-09-13": "September 11 attacks",
+		"2001-09-14": "September 11 attacks",
+		"2004-06-11": "National Day of Mourning for Ronald Reagan",
+		"2007-01-02": "National Day of Mourning for Gerald Ford",
+		"2012-10-29": "Hurricane Sandy",
+		"2012-10-30": "Hurricane Sandy",
+		"2018-12-05": "National Day of Mourning for George H. W. Bush",
+		"2025-01-09": "National Day of Mourning for Jimmy Carter",
+	}
+)
+
+// CalendarFor returns the trading calendar of the given exchange, either a Yahoo! finance exchange code such as "NMS"
+// or the code of a calendar such as "NASDAQ".
+func CalendarFor(exchange string) (*ExchangeCalendar, bool) {
+	exchange = strings.ToUpper(exchange)
+	if cal, ok := exchangeCalendars[exchange]; ok {
+		return cal, true
+	}
+	for _, cal := range []*ExchangeCalendar{CalendarNYSE, CalendarNasdaq, CalendarTSX, CalendarLSE, CalendarXETRA} {
+		if cal.Code == exchange {
+			return cal, true
+		}
+	}
+	return nil, false
+}
+
+// Location returns the time zone of the exchange, or UTC if the time zone database is not available.
+func (cal *ExchangeCalendar) Location() *time.Location {
+	return exchangeLocation(cal.TimeZone, "", nil)
+}
+
+// Holiday tells whether the exchange is closed for a holiday on the day of the given time, in the exchange's time
+// zone, and returns its name.
+func (cal *ExchangeCalendar) Holiday(day time.Time) (string, bool) {
+	d, ok := cal.special(day.In(cal.Location()))
+	if !ok || d.early {
+		return "", false
+	}
+	return d.name, true
+}
+
+// IsTradingDay tells whether the exchange trades on the day of the given time, in the exchange's time zone.
+func (cal *ExchangeCalendar) IsTradingDay(day time.Time) bool {
+	_, ok := cal.Session(day)
+	return ok
+}
+
+// Session returns the trading session of the day of the given time, in the exchange's time zone. It returns false on
+// weekends and holidays.
+func (cal *ExchangeCalendar) Session(day time.Time) (TradingSession, bool) {
+	loc := cal.Location()
+	day = day.In(loc)
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return TradingSession{}, false
+	}
+
+	special, isSpecial := cal.special(day)
+	if isSpecial && !special.early {
+		return TradingSession{}, false
+	}
+
+	year, month, dom := day.Date()
+	at := func(c clock) time.Time { return time.Date(year, month, dom, 0, int(c), 0, 0, loc) }
+
+	session := TradingSession{
+		Date:         at(0),
+		PreStart:     at(cal.preOpen),
+		RegularStart: at(cal.open),
+		RegularEnd:   at(cal.close),
+		PostEnd:      at(cal.postClose),
+	}
+	if isSpecial {
+		session.EarlyClose = true
+		session.EarlyCloseReason = special.name
+		session.RegularEnd = at(cal.earlyClose)
+		session.PostEnd = session.RegularEnd.Add(time.Duration(cal.postClose-cal.close) * time.Minute)
+	}
+	return session, true
+}
+
+// StateAt returns the state of the market at the given time: MarketStatePre, MarketStateRegular, MarketStatePost or
+// MarketStateClosed.
+func (cal *ExchangeCalendar) StateAt(t time.Time) MarketState {
+	session, ok := cal.Session(t)
+	if !ok {
+		return MarketStateClosed
+	}
+	return session.StateAt(t)
+}
+
+// IsOpen tells whether the regular session of the exchange is open at the given time.
+func (cal *ExchangeCalendar) IsOpen(t time.Time) bool {
+	return cal.StateAt(t) == MarketStateRegular
+}
+
+// LastFullSession returns the last session whose regular hours were complete at the given time.
+func (cal *ExchangeCalendar) LastFullSession(t time.Time) TradingSession {
+	day := t.In(cal.Location())
+	for {
+		if session, ok := cal.Session(day); ok && !session.RegularEnd.After(t) {
+			return session
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+// NextSession returns the first session whose regular hours aren't over at the given time. It's the current session
+// while the regular market is open.
+func (cal *ExchangeCalendar) NextSession(t time.Time) TradingSession {
+	day := t.In(cal.Location())
+	for {
+		if session, ok := cal.Session(day); ok && session.RegularEnd.After(t) {
+			return session
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// CheckTradingPeriod compares the regular session the calendar expects with the current trading period Yahoo! finance
+// reported in a chart's metadata, and returns an error describing any mismatch.
+func (cal *ExchangeCalendar) CheckTradingPeriod(meta *ChartMeta) error {
+	regular := meta.CurrentTradingPeriod.Regular
+	if regular.Start == 0 {
+		return nil
+	}
+
+	start, end := regular.Start.Time(), regular.End.Time()
+	session, ok := cal.Session(start)
+	if !ok {
+		return fmt.Errorf("%s: trading period reported on %s, which the calendar has as closed",
+			cal.Code, start.In(cal.Location()).Format(time.DateOnly))
+	}
+	if !session.RegularStart.Equal(start) || !session.RegularEnd.Equal(end) {
+		return fmt.Errorf("%s: regular session reported from %v to %v, expected from %v to %v", cal.Code,
+			start.In(cal.Location()), end.In(cal.Location()), session.RegularStart, session.RegularEnd)
+	}
+	return nil
+}
+
+// StateAt returns the state of the market at the given time, assumed to be on the day of the session.
+func (session TradingSession) StateAt(t time.Time) MarketState {
+	switch {
+	case t.Before(session.PreStart) || !t.Before(session.PostEnd):
+		return MarketStateClosed
+	case t.Before(session.RegularStart):
+		return MarketStatePre
+	case t.Before(session.RegularEnd):
+		return MarketStateRegular
+	default:
+		return MarketStatePost
+	}
+}
+
+// special returns the holiday or early close on the given day, if any.
+func (cal *ExchangeCalendar) special(day time.Time) (calendarDay, bool) {
+	cal.mu.Lock()
+	defer cal.mu.Unlock()
+
+	year := day.Year()
+	if cal.years == nil {
+		cal.years = map[int]map[string]calendarDay{}
+	}
+	days, ok := cal.years[year]
+	if !ok {
+		days = map[string]calendarDay{}
+		for _, d := range cal.holidays(year) {
+			key := d.date.Format(time.DateOnly)
+			// A closure has precedence over an early close falling on the same day.
+			if existing, found := days[key]; !found || existing.early {
+				days[key] = d
+			}
+		}
+		cal.years[year] = days
+	}
+
+	d, ok := days[day.Format(time.DateOnly)]
+	return d, ok
+}
+
+// date returns the midnight UTC of the given day. Holidays are only compared by date, so their time zone doesn't
+// matter.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday returns the nth given weekday of the month, or the last one if n is negative.
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := date(year, month+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+	first := date(year, month, 1)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// easter returns the date of Easter Sunday of the given year, in the Gregorian calendar.
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// observed returns the weekday a holiday falling on a weekend is observed on: the previous Friday for a Saturday and
+// the next Monday for a Sunday.
+func observed(d time.Time) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// nextWeekday returns the given day if it's a weekday, or the following Monday.
+func nextWeekday(d time.Time) time.Time {
+	for d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// usHolidays returns the holidays and early closes of the NYSE and Nasdaq for the given year.
+func usHolidays(year int) []calendarDay {
+	days := []calendarDay{
+		{date: nthWeekday(year, time.February, time.Monday, 3), name: "Washington's Birthday"},
+		{date: easter(year).AddDate(0, 0, -2), name: "Good Friday"},
+		{date: nthWeekday(year, time.May, time.Monday, -1), name: "Memorial Day"},
+		{date: observed(date(year, time.July, 4)), name: "Independence Day"},
+		{date: nthWeekday(year, time.September, time.Monday, 1), name: "Labor Day"},
+		{date: nthWeekday(year, time.November, time.Thursday, 4), name: "Thanksgiving Day"},
+		{date: observed(date(year, time.December, 25)), name: "Christmas Day"},
+	}
+
+	// New Year's Day falling on a Saturday isn't observed, as the previous Friday ends an accounting year.
+	if newYear := date(year, time.January, 1); newYear.Weekday() != time.Saturday {
+		days = append(days, calendarDay{date: observed(newYear), name: "New Year's Day"})
+	}
+	if year >= 1998 {
+		days = append(days, calendarDay{date: nthWeekday(year, time.January, time.Monday, 3), name: "Martin Luther King Jr. Day"})
+	}
+	if year >= 2022 {
+		days = append(days, calendarDay{date: observed(date(year, time.June, 19)), name: "Juneteenth"})
+	}
+
+	// Early closes, only when they fall on a trading day.
+	if july3 := date(year, time.July, 3); july3.Weekday() >= time.Monday && july3.Weekday() <= time.Thursday {
+		days = append(days, calendarDay{date: july3, name: "Independence Day", early: true})
+	}
+	days = append(days, calendarDay{
+		date: nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1), name: "Thanksgiving Day", early: true,
+	})
+	if eve := date(year, time.December, 24); eve.Weekday() >= time.Monday && eve.Weekday() <= time.Thursday {
+		days = append(days, calendarDay{date: eve, name: "Christmas Eve", early: true})
+	}
+
+	for d, name := range usClosures {
+		if t, err := time.Parse(time.DateOnly, d); err == nil && t.Year() == year {
+			days = append(days, calendarDay{date: t, name: name})
+		}
+	}
+
+	return days
+}
+
+// canadaHolidays returns the holidays and early closes of the TSX for the given year.
+func canadaHolidays(year int) []calendarDay {
+	christmas := date(year, time.December, 25)
+	boxingDay := date(year, time.December, 26)
+	// Christmas and Boxing Day falling on a weekend are observed on the following weekdays.
+	switch christmas.Weekday() {
+	case time.Saturday:
+		christmas, boxingDay = christmas.AddDate(0, 0, 2), boxingDay.AddDate(0, 0, 2)
+	case time.Sunday:
+		christmas, boxingDay = christmas.AddDate(0, 0, 1), boxingDay.AddDate(0, 0, 1)
+	case time.Friday:
+		boxingDay = boxingDay.AddDate(0, 0, 2)
+	}
+
+	days := []calendarDay{
+		{date: nextWeekday(date(year, time.January, 1)), name: "New Year's Day"},
+		{date: easter(year).AddDate(0, 0, -2), name: "Good Friday"},
+		{date: victoriaDay(year), name: "Victoria Day"},
+		{date: nextWeekday(date(year, time.July, 1)), name: "Canada Day"},
+		{date: nthWeekday(year, time.August, time.Monday, 1), name: "Civic Holiday"},
+		{date: nthWeekday(year, time.September, time.Monday, 1), name: "Labour Day"},
+		{date: nthWeekday(year, time.October, time.Monday, 2), name: "Thanksgiving Day"},
+		{date: christmas, name: "Christmas Day"},
+		{date: boxingDay, name: "Boxing Day"},
+	}
+	if year >= 2008 {
+		days = append(days, calendarDay{date: nthWeekday(year, time.February, time.Monday, 3), name: "Family Day"})
+	}
+	if eve := date(year, time.December, 24); eve.Weekday() >= time.Monday && eve.Weekday() <= time.Friday {
+		days = append(days, calendarDay{date: eve, name: "Christmas Eve", early: true})
+	}
+
+	return days
+}
+
+// victoriaDay returns the date of Victoria Day, the Monday preceding May 25, for the given year.
+func victoriaDay(year int) time.Time {
+	d := date(year, time.May, 24)
+	return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+}
+
+// ukHolidays returns the holidays and early closes of the LSE for the given year.
+func ukHolidays(year int) []calendarDay {
+	christmas := date(year, time.December, 25)
+	boxingDay := date(year, time.December, 26)
+	switch christmas.Weekday() {
+	case time.Saturday:
+		christmas, boxingDay = christmas.AddDate(0, 0, 2), boxingDay.AddDate(0, 0, 2)
+	case time.Sunday:
+		christmas = christmas.AddDate(0, 0, 2)
+	case time.Friday:
+		boxingDay = boxingDay.AddDate(0, 0, 2)
+	}
+
+	days := []calendarDay{
+		{date: nextWeekday(date(year, time.January, 1)), name: "New Year's Day"},
+		{date: easter(year).AddDate(0, 0, -2), name: "Good Friday"},
+		{date: easter(year).AddDate(0, 0, 1), name: "Easter Monday"},
+		{date: nthWeekday(year, time.May, time.Monday, 1), name: "Early May Bank Holiday"},
+		{date: nthWeekday(year, time.May, time.Monday, -1), name: "Spring Bank Holiday"},
+		{date: nthWeekday(year, time.August, time.Monday, -1), name: "Summer Bank Holiday"},
+		{date: christmas, name: "Christmas Day"},
+		{date: boxingDay, name: "Boxing Day"},
+	}
+	for _, eve := range []calendarDay{
+		{date: date(year, time.December, 24), name: "Christmas Eve", early: true},
+		{date: date(year, time.December, 31), name: "New Year's Eve", early: true},
+	} {
+		if eve.date.Weekday() != time.Saturday && eve.date.Weekday() != time.Sunday {
+			days = append(days, eve)
+		}
+	}
+
+	return days
+}
+
+// germanyHolidays returns the holidays of XETRA for the given year. Holidays falling on a weekend aren't observed.
+func germanyHolidays(year int) []calendarDay {
+	return []calendarDay{
+		{date: date(year, time.January, 1), name: "New Year's Day"},
+		{date: easter(year).AddDate(0, 0, -2), name: "Good Friday"},
+		{date: easter(year).AddDate(0, 0, 1), name: "Easter Monday"},
+		{date: date(year, time.May, 1), name: "Labour Day"},
+		{date: date(year, time.December, 24), name: "Christmas Eve"},
+		{date: date(year, time.December, 25), name: "Christmas Day"},
+		{date: date(year, time.December, 26), name: "Boxing Day"},
+		{date: date(year, time.December, 31), name: "New Year's Eve"},
+	}
+}
+
+// ensure ExchangeCalendar can be used to find gaps in series of bars.
+var _ TradingCalendar = (*ExchangeCalendar)(nil)
@@ -0,0 +1,308 @@
+package dorfyn
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// chartResponse is a yfin chart response.
+type chartResponse struct {
+	Inner struct {
+		Result []chartResult `json:"result"`
+		Error  *yError       `json:"error"`
+	} `json:"chart"`
+}
+
+// chartResult is a single result of a yfin chart response.
+type chartResult struct {
+	Meta       ChartMeta   `json:"meta"`
+	Timestamp  []UnixTime  `json:"timestamp"`
+	Events     chartEvents `json:"events"`
+	Indicators struct {
+		Quote []struct {
+			Open   []*decimal.Decimal `json:"open"`
+			Low    []*decimal.Decimal `json:"low"`
+			High   []*decimal.Decimal `json:"high"`
+			Close  []*decimal.Decimal `json:"close"`
+			Volume []*int             `json:"volume"`
+		} `json:"quote"`
+		AdjClose []struct {
+			AdjClose []*decimal.Decimal `json:"adjclose"`
+		} `json:"adjclose"`
+	} `json:"indicators"`
+}
+
+// chartEvents is the events block of a yfin chart response. Events are keyed by their timestamp, as a string.
+type chartEvents struct {
+	Dividends map[string]struct {
+		Amount decimal.Decimal `json:"amount"`
+		Date   UnixTime        `json:"date"`
+	} `json:"dividends"`
+	Splits map[string]struct {
+		Date        UnixTime        `json:"date"`
+		Numerator   decimal.Decimal `json:"numerator"`
+		Denominator decimal.Decimal `json:"denominator"`
+		SplitRatio  string          `json:"splitRatio"`
+	} `json:"splits"`
+	CapitalGains map[string]struct {
+		Amount decimal.Decimal `json:"amount"`
+		Date   UnixTime        `json:"date"`
+	} `json:"capitalGains"`
+}
+
+const (
+	// yFinChartAPI is the path to the Yahoo! finance chart API. It must be followed by a symbol.
+	yFinChartAPI string = "/v8/finance/chart/"
+
+	// chartEventsParam requests all the corporate action events from the chart API.
+	chartEventsParam = "div,splits,capitalGains"
+)
+
+// Interval is the duration of the bars of a chart.
+type Interval string
+
+const (
+	// Interval1Min is for one minute bars.
+	Interval1Min Interval = "1m"
+	// Interval2Min is for two minutes bars.
+	Interval2Min Interval = "2m"
+	// Interval5Min is for five minutes bars.
+	Interval5Min Interval = "5m"
+	// Interval15Min is for fifteen minutes bars.
+	Interval15Min Interval = "15m"
+	// Interval30Min is for thirty minutes bars.
+	Interval30Min Interval = "30m"
+	// Interval60Min is for sixty minutes bars.
+	Interval60Min Interval = "60m"
+	// Interval90Min is for ninety minutes bars.
+	Interval90Min Interval = "90m"
+	// Interval1Hour is for one hour bars.
+	Interval1Hour Interval = "1h"
+	// Interval1Day is for daily bars.
+	Interval1Day Interval = "1d"
+	// Interval5Day is for five days bars.
+	Interval5Day Interval = "5d"
+	// Interval1Week is for weekly bars.
+	Interval1Week Interval = "1wk"
+	// Interval1Month is for monthly bars.
+	Interval1Month Interval = "1mo"
+	// Interval3Month is for quarterly bars.
+	Interval3Month Interval = "3mo"
+)
+
+// ChartParams holds the options of a GetChart call.
+type ChartParams struct {
+	// Interval is the duration of the bars. Defaults to Interval1Day.
+	Interval Interval
+	// Start is the time of the first bar to return. If zero, Range is used instead.
+	Start time.Time
+	// End is the time after the last bar to return. Defaults to now.
+	End time.Time
+	// Range is the span of the chart, ending now, used when Start is zero: "1d", "5d", "1mo", "3mo", "6mo", "1y",
+	// "2y", "5y", "10y", "ytd" or "max". Defaults to "1mo".
+	Range string
+	// IncludePrePost includes the pre and post market bars of intraday charts.
+	IncludePrePost bool
+}
+
+// Dividend is a cash dividend paid by a security.
+type Dividend struct {
+	// Date is the ex-dividend date, in the exchange's time zone.
+	Date time.Time
+	// Amount is the dividend per share.
+	Amount decimal.Decimal
+}
+
+// Split is a stock split, or a reverse split when the Numerator is smaller than the Denominator.
+type Split struct {
+	// Date is the ex-date of the split, in the exchange's time zone.
+	Date time.Time
+	// Numerator is the number of shares after the split for Denominator shares before it.
+	Numerator decimal.Decimal
+	// Denominator is the number of shares before the split that yield Numerator shares after it.
+	Denominator decimal.Decimal
+}
+
+// CapitalGain is a capital gain distribution paid by a fund.
+type CapitalGain struct {
+	// Date is the ex-date of the distribution, in the exchange's time zone.
+	Date time.Time
+	// Amount is the distribution per share.
+	Amount decimal.Decimal
+}
+
+// Chart is a series of bars for a security, along with the corporate actions that occurred during its span.
+type Chart struct {
+	Meta ChartMeta
+	// Bars are the bars of the chart, in chronological order.
+	Bars []ChartBar
+	// Dividends are the dividends paid during the span of the chart, in chronological order.
+	Dividends []Dividend
+	// Splits are the stock splits that occurred during the span of the chart, in chronological order.
+	Splits []Split
+	// CapitalGains are the capital gain distributions paid during the span of the chart, in chronological order.
+	CapitalGains []CapitalGain
+}
+
+// Factor returns the number of shares after the split for each share before it, e.g. 4 for a 4:1 split.
+func (s Split) Factor() decimal.Decimal {
+	if s.Denominator.IsZero() {
+		return decimal.NewFromInt(1)
+	}
+	return s.Numerator.Div(s.Denominator)
+}
+
+// String returns the split ratio in the "numerator:denominator" form.
+func (s Split) String() string {
+	return s.Numerator.String() + ":" + s.Denominator.String()
+}
+
+// ParseSplitRatio parses a split ratio in the "numerator:denominator" form used by Yahoo! finance, e.g. "4:1" or
+// "1:10". The "numerator/denominator" form is accepted as well.
+func ParseSplitRatio(ratio string) (numerator decimal.Decimal, denominator decimal.Decimal, err error) {
+	parts := strings.FieldsFunc(ratio, func(r rune) bool { return r == ':' || r == '/' })
+	if len(parts) != 2 {
+		return decimal.Zero, decimal.Zero, CreateArgumentError(fmt.Sprintf("Invalid split ratio %q", ratio))
+	}
+
+	numerator, err = decimal.NewFromString(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return decimal.Zero, decimal.Zero, CreateArgumentError(fmt.Sprintf("Invalid split ratio %q: %v", ratio, err))
+	}
+	denominator, err = decimal.NewFromString(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return decimal.Zero, decimal.Zero, CreateArgumentError(fmt.Sprintf("Invalid split ratio %q: %v", ratio, err))
+	}
+	if !numerator.IsPositive() || !denominator.IsPositive() {
+		return decimal.Zero, decimal.Zero,
+			CreateArgumentError(fmt.Sprintf("Invalid split ratio %q: terms must be positive", ratio))
+	}
+
+	return numerator, denominator, nil
+}
+
+// GetChart returns the chart of the given symbol, including its dividends, splits and capital gains. params may be
+// nil to use the default options.
+func GetChart(symbol string, params *ChartParams) (*Chart, error) {
+	if symbol == "" {
+		return nil, CreateArgumentError("No symbol provided to GetChart")
+	}
+	if params == nil {
+		params = &ChartParams{}
+	}
+
+	interval := params.Interval
+	if interval == "" {
+		interval = Interval1Day
+	}
+
+	qp := queryParams{
+		"interval":       string(interval),
+		"events":         chartEventsParam,
+		"includePrePost": strconv.FormatBool(params.IncludePrePost),
+	}
+	if params.Start.IsZero() {
+		qp["range"] = params.Range
+		if params.Range == "" {
+			qp["range"] = "1mo"
+		}
+	} else {
+		end := params.End
+		if end.IsZero() {
+			end = time.Now()
+		}
+		if !params.Start.Before(end) {
+			return nil, CreateArgumentError("Empty time range provided to GetChart")
+		}
+		qp["period1"] = strconv.FormatInt(params.Start.Unix(), 10)
+		qp["period2"] = strconv.FormatInt(end.Unix(), 10)
+	}
+
+	resp := chartResponse{}
+	err := client.call(yFinChartAPI+url.PathEscape(symbol), qp, &resp)
+	if err != nil {
+		return nil, createRemoteError(err)
+	}
+	if resp.Inner.Error != nil {
+		return nil, createRemoteError(resp.Inner.Error)
+	}
+	if len(resp.Inner.Result) == 0 {
+		return nil, createRemoteError(&yError{Code: "Not Found", Description: "No chart returned for " + symbol})
+	}
+
+	return resp.Inner.Result[0].chart(), nil
+}
+
+// chart builds a Chart out of the chart result.
+func (r *chartResult) chart() *Chart {
+	chart := &Chart{Meta: r.Meta}
+	loc := r.Meta.Location()
+
+	// Missing values, which Yahoo! finance sends as nulls, are left to zero, and the bars missing any of their prices
+	// are flagged as null.
+	value := func(values []*decimal.Decimal, i int, null *bool) decimal.Decimal {
+		if i < len(values) && values[i] != nil {
+			return *values[i]
+		}
+		*null = true
+		return decimal.Zero
+	}
+
+	chart.Bars = make([]ChartBar, 0, len(r.Timestamp))
+	for i, ts := range r.Timestamp {
+		bar := ChartBar{Timestamp: ts, Null: true}
+		if len(r.Indicators.Quote) > 0 {
+			q := r.Indicators.Quote[0]
+			bar.Null = false
+			bar.Open = value(q.Open, i, &bar.Null)
+			bar.Low = value(q.Low, i, &bar.Null)
+			bar.High = value(q.High, i, &bar.Null)
+			bar.Close = value(q.Close, i, &bar.Null)
+			if i < len(q.Volume) && q.Volume[i] != nil {
+				bar.Volume = *q.Volume[i]
+			}
+		}
+		if len(r.Indicators.AdjClose) > 0 && !bar.Null {
+			var null bool
+			if bar.AdjClose = value(r.Indicators.AdjClose[0].AdjClose, i, &null); null {
+				bar.AdjClose = bar.Close
+			}
+		} else {
+			bar.AdjClose = bar.Close
+		}
+		chart.Bars = append(chart.Bars, bar)
+	}
+
+	for _, d := range r.Events.Dividends {
+		chart.Dividends = append(chart.Dividends, Dividend{Date: d.Date.In(loc), Amount: d.Amount})
+	}
+	sort.Slice(chart.Dividends, func(i, j int) bool { return chart.Dividends[i].Date.Before(chart.Dividends[j].Date) })
+
+	for _, s := range r.Events.Splits {
+		split := Split{Date: s.Date.In(loc), Numerator: s.Numerator, Denominator: s.Denominator}
+		// The ratio is the most reliable form: the numerator and denominator are sometimes missing or swapped.
+		if num, den, err := ParseSplitRatio(s.SplitRatio); err == nil {
+			split.Numerator, split.Denominator = num, den
+		} else if !split.Numerator.IsPositive() || !split.Denominator.IsPositive() {
+			logError("Ignoring split on %v: %v\n", split.Date, err)
+			continue
+		}
+		chart.Splits = append(chart.Splits, split)
+	}
+	sort.Slice(chart.Splits, func(i, j int) bool { return chart.Splits[i].Date.Before(chart.Splits[j].Date) })
+
+	for _, g := range r.Events.CapitalGains {
+		chart.CapitalGains = append(chart.CapitalGains, CapitalGain{Date: g.Date.In(loc), Amount: g.Amount})
+	}
+	sort.Slice(chart.CapitalGains, func(i, j int) bool {
+		return chart.CapitalGains[i].Date.Before(chart.CapitalGains[j].Date)
+	})
+
+	return chart
+}
@@ -0,0 +1,225 @@
+package dorfyn
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// NullPolicy selects what Clean does with null bars, the bars Yahoo! finance sends without prices.
+type NullPolicy int
+
+const (
+	// NullKeep keeps null bars as they are.
+	NullKeep NullPolicy = iota
+	// NullDrop removes null bars.
+	NullDrop
+	// NullForwardFill replaces the prices of null bars with the close of the previous bar, with no volume. Null bars
+	// without a previous bar are removed.
+	NullForwardFill
+)
+
+// IssueKind is the kind of an issue found by Clean.
+type IssueKind string
+
+const (
+	// IssueNull is a bar without prices.
+	IssueNull IssueKind = "null"
+	// IssueDuplicate is a bar with the same timestamp as a previous one. Only the last one is kept.
+	IssueDuplicate IssueKind = "duplicate"
+	// IssueZeroVolume is a bar without volume whose prices are all the same, typically a placeholder row.
+	IssueZeroVolume IssueKind = "zero-volume"
+	// IssueGap is a span without bars where the trading calendar or the interval expected some.
+	IssueGap IssueKind = "gap"
+	// IssueInconsistent is a bar whose high is lower than its low, open or close, or whose low is higher than its
+	// open or close.
+	IssueInconsistent IssueKind = "inconsistent"
+	// IssueOutlier is a bar whose close moved more than the outlier threshold from the previous close.
+	IssueOutlier IssueKind = "outlier"
+)
+
+const (
+	// defaultOutlierThreshold is the default relative close-to-close move above which a bar is flagged as an outlier.
+	defaultOutlierThreshold = 0.5
+)
+
+// TradingCalendar tells which days an exchange trades on.
+type TradingCalendar interface {
+	// IsTradingDay tells whether the exchange trades on the day of the given time, in the time's location.
+	IsTradingDay(day time.Time) bool
+}
+
+// WeekdayCalendar is a TradingCalendar trading every weekday, ignoring holidays.
+type WeekdayCalendar struct{}
+
+// IsTradingDay tells whether the given day is a weekday.
+func (WeekdayCalendar) IsTradingDay(day time.Time) bool {
+	return day.Weekday() != time.Saturday && day.Weekday() != time.Sunday
+}
+
+// CleanParams holds the options of a Clean call.
+type CleanParams struct {
+	// Nulls selects what to do with null bars. Defaults to NullKeep.
+	Nulls NullPolicy
+	// DropZeroVolume removes the bars reported as IssueZeroVolume.
+	DropZeroVolume bool
+	// BarDuration is the expected spacing of intraday bars. Within a day, a larger spacing is reported as a gap. Zero
+	// means the bars are daily, in which case only missing trading days are reported.
+	BarDuration time.Duration
+	// Calendar is used to find missing trading days, e.g. CalendarNYSE. Defaults to WeekdayCalendar.
+	Calendar TradingCalendar
+	// Location is the time zone used to find the day of the bars, usually the exchange's one. Defaults to UTC.
+	Location *time.Location
+	// OutlierThreshold is the relative close-to-close move above which a bar is reported as an outlier, e.g. 0.5 for
+	// 50%. Defaults to 0.5.
+	OutlierThreshold float64
+}
+
+// Issue is a problem found in a series of bars by Clean.
+type Issue struct {
+	Kind IssueKind
+	// Timestamp is the timestamp of the offending bar. For gaps, it's the timestamp of the bar following the gap.
+	Timestamp UnixTime
+	// Missing lists the trading days missing before the bar, for gaps between days.
+	Missing []time.Time
+	// Detail is a human-readable description of the issue.
+	Detail string
+}
+
+// String returns a human-readable description of the issue.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s at %d: %s", i.Kind, i.Timestamp, i.Detail)
+}
+
+// Clean validates the given bars and returns a cleaned up copy of them, in chronological order, along with the
+// issues found. Duplicated timestamps are always resolved by keeping the last bar, null and zero-volume bars are
+// handled according to params, and gaps, inconsistent bars and outliers are only reported.
+func Clean(bars []ChartBar, params CleanParams) ([]ChartBar, []Issue) {
+	if params.Calendar == nil {
+		params.Calendar = WeekdayCalendar{}
+	}
+	if params.Location == nil {
+		params.Location = time.UTC
+	}
+	if params.OutlierThreshold <= 0 {
+		params.OutlierThreshold = defaultOutlierThreshold
+	}
+
+	var issues []Issue
+	report := func(kind IssueKind, ts UnixTime, format string, v ...any) {
+		issues = append(issues, Issue{Kind: kind, Timestamp: ts, Detail: fmt.Sprintf(format, v...)})
+	}
+
+	sorted := make([]ChartBar, len(bars))
+	copy(sorted, bars)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	// Resolve duplicates first, so the other checks look at the bars that are kept.
+	var unique []ChartBar
+	for _, bar := range sorted {
+		if n := len(unique); n > 0 && unique[n-1].Timestamp == bar.Timestamp {
+			report(IssueDuplicate, bar.Timestamp, "duplicated timestamp, keeping the last bar")
+			unique[n-1] = bar
+			continue
+		}
+		unique = append(unique, bar)
+	}
+
+	threshold := decimal.NewFromFloat(params.OutlierThreshold)
+	var cleaned []ChartBar
+	var previous *ChartBar
+	// lastClose is the close of the last bar with prices, which outliers are measured against, as kept null bars
+	// have none.
+	var lastClose decimal.Decimal
+	for _, bar := range unique {
+		if bar.isPlaceholder() {
+			report(IssueNull, bar.Timestamp, "bar without prices")
+			switch params.Nulls {
+			case NullDrop:
+				continue
+			case NullForwardFill:
+				if previous == nil {
+					continue
+				}
+				bar = ChartBar{
+					Open:      previous.Close,
+					Low:       previous.Close,
+					High:      previous.Close,
+					Close:     previous.Close,
+					AdjClose:  previous.AdjClose,
+					Timestamp: bar.Timestamp,
+				}
+			}
+		} else {
+			if bar.Volume == 0 && bar.Open.Equal(bar.Close) && bar.High.Equal(bar.Low) && bar.Open.Equal(bar.High) {
+				report(IssueZeroVolume, bar.Timestamp, "flat bar without volume")
+				if params.DropZeroVolume {
+					continue
+				}
+			}
+			if bar.High.LessThan(bar.Low) || bar.High.LessThan(decimal.Max(bar.Open, bar.Close)) ||
+				bar.Low.GreaterThan(decimal.Min(bar.Open, bar.Close)) {
+				report(IssueInconsistent, bar.Timestamp, "open %v, high %v, low %v, close %v",
+					bar.Open, bar.High, bar.Low, bar.Close)
+			}
+			if lastClose.IsPositive() {
+				move := bar.Close.Div(lastClose).Sub(decimal.NewFromInt(1))
+				if move.Abs().GreaterThan(threshold) {
+					report(IssueOutlier, bar.Timestamp, "close moved %v%% from %v to %v",
+						move.Shift(2).Round(1), lastClose, bar.Close)
+				}
+			}
+		}
+
+		if previous != nil {
+			if gap := params.gap(*previous, bar); gap != nil {
+				issues = append(issues, *gap)
+			}
+		}
+
+		cleaned = append(cleaned, bar)
+		previous = &cleaned[len(cleaned)-1]
+		if !bar.isPlaceholder() {
+			lastClose = bar.Close
+		}
+	}
+
+	return cleaned, issues
+}
+
+// gap returns the gap between the two given consecutive bars, or nil if there is none.
+func (params *CleanParams) gap(previous ChartBar, bar ChartBar) *Issue {
+	from := previous.Timestamp.In(params.Location)
+	to := bar.Timestamp.In(params.Location)
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, params.Location)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, params.Location)
+
+	if fromDay.Equal(toDay) {
+		if params.BarDuration > 0 && to.Sub(from) > params.BarDuration {
+			return &Issue{
+				Kind:      IssueGap,
+				Timestamp: bar.Timestamp,
+				Detail:    fmt.Sprintf("%v without bars, expected one every %v", to.Sub(from), params.BarDuration),
+			}
+		}
+		return nil
+	}
+
+	var missing []time.Time
+	for day := fromDay.AddDate(0, 0, 1); day.Before(toDay); day = day.AddDate(0, 0, 1) {
+		if params.Calendar.IsTradingDay(day) {
+			missing = append(missing, day)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &Issue{
+		Kind:      IssueGap,
+		Timestamp: bar.Timestamp,
+		Missing:   missing,
+		Detail:    fmt.Sprintf("%d trading day(s) missing since %s", len(missing), fromDay.Format(time.DateOnly)),
+	}
+}
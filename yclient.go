@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,6 +19,8 @@ type queryParams map[string]string
 
 // yClient is a Yahoo Finance client.
 type yClient struct {
+	// mu guards the session fields below, which are shared by concurrent calls.
+	mu      sync.Mutex
 	expiry  time.Time
 	cookies string
 	crumb   string
@@ -173,8 +176,26 @@ func (client *yClient) refreshCrumb() error {
 	return nil
 }
 
-// newRequest creates a new Yahoo Finance request for the given path.
-func (client *yClient) newRequest(path string) (*http.Request, error) {
+// session returns the cookies and crumb to use for a request, refreshing them first if they have expired.
+func (client *yClient) session() (string, string, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	// Check if the cookies have expired.
+	if client.expiry.Before(time.Now()) {
+		// Refresh the cookies and crumb.
+		err := client.refreshCrumb()
+		if err != nil {
+			logError("Can't refresh crumb: %v\n", err)
+			return "", "", err
+		}
+	}
+
+	return client.cookies, client.crumb, nil
+}
+
+// newRequest creates a new Yahoo Finance request for the given path, authenticated with the given cookies.
+func (client *yClient) newRequest(path string, cookies string) (*http.Request, error) {
 	logInfo("Creating new request for path: %s\n", path)
 
 	path = yFinURL + path
@@ -189,7 +210,7 @@ func (client *yClient) newRequest(path string) (*http.Request, error) {
 		"Accept-Language": {"en-US,en;q=0.5"},
 		"Connection":      {"keep-alive"},
 		"Content-Type":    {"application/json"},
-		"Cookie":          {client.cookies},
+		"Cookie":          {cookies},
 		"Host":            {"query1.finance.yahoo.com"},
 		"Origin":          {"https://finance.yahoo.com"},
 		"Referer":         {"https://finance.yahoo.com"},
@@ -203,10 +224,10 @@ func (client *yClient) newRequest(path string) (*http.Request, error) {
 	return req, nil
 }
 
-// do is used by Call to execute an API request and parse the response. It uses
-// the backend's HTTP client to execute the request and unmarshal the response
-// into v. It also handles unmarshaling errors returned by the API.
-func (client *yClient) do(req *http.Request, v interface{}) error {
+// do is used by fetch to execute an API request. It uses the backend's HTTP
+// client to execute the request and returns the body of the response. It also
+// handles the error statuses returned by the API.
+func (client *yClient) do(req *http.Request) ([]byte, error) {
 	logInfo("Requesting %v %v%v\n", req.Method, req.URL.Host, req.URL.Path)
 
 	start := time.Now()
@@ -217,7 +238,7 @@ func (client *yClient) do(req *http.Request, v interface{}) error {
 
 	if err != nil {
 		logError("Request to api failed: %v\n", err)
-		return err
+		return nil, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -229,7 +250,7 @@ func (client *yClient) do(req *http.Request, v interface{}) error {
 	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
 		logError("Can't parse response: %v\n", err)
-		return err
+		return nil, err
 	}
 
 	// convert the response to a string
@@ -254,49 +275,63 @@ func (client *yClient) do(req *http.Request, v interface{}) error {
 
 	if res.StatusCode >= 400 {
 		logError("API error: %q\n", resBody)
-		return fmt.Errorf("error response received from upstream api: %s", res.Status)
+		return nil, fmt.Errorf("error response received from upstream api: %s", res.Status)
 	}
 
 	logDebug("API response: %q\n", resBody)
 
-	if v != nil {
-		return json.Unmarshal(resBody, v)
-	}
-
-	return nil
+	return resBody, nil
 }
 
-// call is used by the public API methods to execute an API request.
+// call is used by the public API methods to execute an API request and unmarshal the response into v. The response
+// is served from the cache when one is enabled.
 func (client *yClient) call(path string, params queryParams, v interface{}) error {
 	logInfo("Calling \"%s\" with params %v\n", path, params)
 
-	// Check if the cookies have expired.
-	if client.expiry.Before(time.Now()) {
-		// Refresh the cookies and crumb.
-		err := client.refreshCrumb()
-		if err != nil {
-			logError("Can't refresh crumb: %v\n", err)
-			return err
-		}
+	fetch := func() ([]byte, error) { return client.fetch(path, params) }
+
+	var body []byte
+	var err error
+	if c := activeCache(); c != nil {
+		body, err = c.get(path, params, fetch)
+	} else {
+		body, err = fetch()
+	}
+	if err != nil {
+		return err
+	}
+
+	if v != nil {
+		return json.Unmarshal(body, v)
 	}
 
-	if client.crumb != "" {
-		params["crumb"] = client.crumb
+	return nil
+}
+
+// fetch executes an API request for the given path and parameters, and returns the body of the response. The
+// parameters are not modified, so they can be shared by concurrent fetches.
+func (client *yClient) fetch(path string, params queryParams) ([]byte, error) {
+	cookies, crumb, err := client.session()
+	if err != nil {
+		return nil, err
 	}
 
 	var values = url.Values{}
-	if len(params) > 0 {
-		for key, val := range params {
-			values.Add(key, val)
-		}
+	for key, val := range params {
+		values.Add(key, val)
+	}
+	if crumb != "" {
+		values.Set("crumb", crumb)
+	}
+	if len(values) > 0 {
 		path += "?" + values.Encode()
 	}
 
-	req, err := client.newRequest(path)
+	req, err := client.newRequest(path, cookies)
 	if err != nil {
 		logError("Can't create api request: %v\n", err)
-		return err
+		return nil, err
 	}
 
-	return client.do(req, v)
+	return client.do(req)
 }
@@ -0,0 +1,171 @@
+package dorfyn
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+// DecimalQuote is a Quote whose price, change and ratio fields are decoded as exact decimals rather than floats.
+// The embedded Quote is fully populated, so non-numeric fields and the original float values remain available,
+// the latter through q.Quote.<Field>. Price and change fields are rounded to the number of places given by PriceHint.
+type DecimalQuote struct {
+	Quote
+
+	Ask                               *decimal.Decimal `json:"ask,omitempty"`
+	Bid                               *decimal.Decimal `json:"bid,omitempty"`
+	BookValue                         *decimal.Decimal `json:"bookValue,omitempty"`
+	DividendRate                      *decimal.Decimal `json:"dividendRate,omitempty"`
+	DividendYield                     *decimal.Decimal `json:"dividendYield,omitempty"`
+	EpsCurrentYear                    *decimal.Decimal `json:"epsCurrentYear,omitempty"`
+	EpsForward                        *decimal.Decimal `json:"epsForward,omitempty"`
+	EpsTrailingTwelveMonths           *decimal.Decimal `json:"epsTrailingTwelveMonths,omitempty"`
+	FiftyDayAverage                   *decimal.Decimal `json:"fiftyDayAverage,omitempty"`
+	FiftyDayAverageChange             *decimal.Decimal `json:"fiftyDayAverageChange,omitempty"`
+	FiftyDayAverageChangePercent      *decimal.Decimal `json:"fiftyDayAverageChangePercent,omitempty"`
+	FiftyTwoWeekChangePercent         *decimal.Decimal `json:"fiftyTwoWeekChangePercent,omitempty"`
+	FiftyTwoWeekHigh                  *decimal.Decimal `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekHighChange            *decimal.Decimal `json:"fiftyTwoWeekHighChange"`
+	FiftyTwoWeekHighChangePercent     *decimal.Decimal `json:"fiftyTwoWeekHighChangePercent"`
+	FiftyTwoWeekLow                   *decimal.Decimal `json:"fiftyTwoWeekLow"`
+	FiftyTwoWeekLowChange             *decimal.Decimal `json:"fiftyTwoWeekLowChange"`
+	FiftyTwoWeekLowChangePercent      *decimal.Decimal `json:"fiftyTwoWeekLowChangePercent"`
+	ForwardPE                         *decimal.Decimal `json:"forwardPE,omitempty"`
+	NetAssets                         *decimal.Decimal `json:"netAssets,omitempty"`
+	NetExpenseRatio                   *decimal.Decimal `json:"netExpenseRatio,omitempty"`
+	PostMarketChange                  *decimal.Decimal `json:"postMarketChange"`
+	PostMarketChangePercent           *decimal.Decimal `json:"postMarketChangePercent"`
+	PostMarketPrice                   *decimal.Decimal `json:"postMarketPrice"`
+	PreMarketChange                   *decimal.Decimal `json:"preMarketChange"`
+	PreMarketChangePercent            *decimal.Decimal `json:"preMarketChangePercent"`
+	PreMarketPrice                    *decimal.Decimal `json:"preMarketPrice"`
+	PriceEpsCurrentYear               *decimal.Decimal `json:"priceEpsCurrentYear,omitempty"`
+	PriceToBook                       *decimal.Decimal `json:"priceToBook,omitempty"`
+	RegularMarketChange               *decimal.Decimal `json:"regularMarketChange"`
+	RegularMarketChangePercent        *decimal.Decimal `json:"regularMarketChangePercent"`
+	RegularMarketDayHigh              *decimal.Decimal `json:"regularMarketDayHigh,omitempty"`
+	RegularMarketDayLow               *decimal.Decimal `json:"regularMarketDayLow,omitempty"`
+	RegularMarketOpen                 *decimal.Decimal `json:"regularMarketOpen,omitempty"`
+	RegularMarketPreviousClose        *decimal.Decimal `json:"regularMarketPreviousClose"`
+	RegularMarketPrice                *decimal.Decimal `json:"regularMarketPrice"`
+	Strike                            *decimal.Decimal `json:"strike,omitempty"`
+	TrailingAnnualDividendRate        *decimal.Decimal `json:"trailingAnnualDividendRate,omitempty"`
+	TrailingAnnualDividendYield       *decimal.Decimal `json:"trailingAnnualDividendYield,omitempty"`
+	TrailingPE                        *decimal.Decimal `json:"trailingPE,omitempty"`
+	TrailingThreeMonthNavReturns      *decimal.Decimal `json:"trailingThreeMonthNavReturns,omitempty"`
+	TrailingThreeMonthReturns         *decimal.Decimal `json:"trailingThreeMonthReturns,omitempty"`
+	TwoHundredDayAverage              *decimal.Decimal `json:"twoHundredDayAverage,omitempty"`
+	TwoHundredDayAverageChange        *decimal.Decimal `json:"twoHundredDayAverageChange,omitempty"`
+	TwoHundredDayAverageChangePercent *decimal.Decimal `json:"twoHundredDayAverageChangePercent,omitempty"`
+	YtdReturn                         *decimal.Decimal `json:"ytdReturn,omitempty"`
+}
+
+// UnmarshalJSON decodes a quote, keeping both the float and the decimal representation of its numeric fields.
+func (q *DecimalQuote) UnmarshalJSON(data []byte) error {
+	// plainDecimalQuote has no methods, so decoding into it doesn't recurse into this function.
+	type plainDecimalQuote DecimalQuote
+
+	if err := json.Unmarshal(data, &q.Quote); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, (*plainDecimalQuote)(q)); err != nil {
+		return err
+	}
+
+	if q.PriceHint != nil {
+		roundPrices(int32(*q.PriceHint),
+			q.Ask,
+			q.Bid,
+			q.FiftyDayAverage,
+			q.FiftyDayAverageChange,
+			q.FiftyTwoWeekHigh,
+			q.FiftyTwoWeekHighChange,
+			q.FiftyTwoWeekLow,
+			q.FiftyTwoWeekLowChange,
+			q.PostMarketChange,
+			q.PostMarketPrice,
+			q.PreMarketChange,
+			q.PreMarketPrice,
+			q.RegularMarketChange,
+			q.RegularMarketDayHigh,
+			q.RegularMarketDayLow,
+			q.RegularMarketOpen,
+			q.RegularMarketPreviousClose,
+			q.RegularMarketPrice,
+			q.Strike,
+			q.TwoHundredDayAverage,
+			q.TwoHundredDayAverageChange,
+		)
+	}
+	return nil
+}
+
+// DecimalContract is a Contract whose prices, change and implied volatility are decoded as exact decimals.
+// The embedded Contract keeps the original float values, available through c.Contract.<Field>.
+type DecimalContract struct {
+	Contract
+
+	Strike            decimal.Decimal `json:"strike" csv:"strike"`
+	LastPrice         decimal.Decimal `json:"lastPrice" csv:"lastPrice"`
+	Change            decimal.Decimal `json:"change" csv:"change"`
+	PercentChange     decimal.Decimal `json:"percentChange" csv:"percentChange"`
+	Bid               decimal.Decimal `json:"bid" csv:"bid"`
+	Ask               decimal.Decimal `json:"ask" csv:"ask"`
+	ImpliedVolatility decimal.Decimal `json:"impliedVolatility" csv:"impliedVolatility"`
+}
+
+// UnmarshalJSON decodes a contract, keeping both the float and the decimal representation of its numeric fields.
+func (c *DecimalContract) UnmarshalJSON(data []byte) error {
+	// plainDecimalContract has no methods, so decoding into it doesn't recurse into this function.
+	type plainDecimalContract DecimalContract
+
+	if err := json.Unmarshal(data, &c.Contract); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, (*plainDecimalContract)(c))
+}
+
+// Rounded returns a copy of the contract with its prices and change rounded to priceHint decimal places.
+// Contracts carry no price hint of their own; the one of the underlying's quote is usually the right choice.
+func (c DecimalContract) Rounded(priceHint int) DecimalContract {
+	c.Strike = c.Strike.Round(int32(priceHint))
+	c.LastPrice = c.LastPrice.Round(int32(priceHint))
+	c.Change = c.Change.Round(int32(priceHint))
+	c.Bid = c.Bid.Round(int32(priceHint))
+	c.Ask = c.Ask.Round(int32(priceHint))
+	return c
+}
+
+// Rounded returns a copy of the bar with its prices rounded to priceHint decimal places, as found in ChartMeta.PriceHint.
+// Yahoo! finance chart data is computed in single precision, which shows as values such as 187.19000244140625.
+func (bar ChartBar) Rounded(priceHint int) ChartBar {
+	bar.Open = bar.Open.Round(int32(priceHint))
+	bar.Low = bar.Low.Round(int32(priceHint))
+	bar.High = bar.High.Round(int32(priceHint))
+	bar.Close = bar.Close.Round(int32(priceHint))
+	bar.AdjClose = bar.AdjClose.Round(int32(priceHint))
+	return bar
+}
+
+// ChartBar converts the historical quotation to a ChartBar, with its prices rounded to priceHint decimal places.
+func (h OHLCHistoric) ChartBar(priceHint int) ChartBar {
+	bar := ChartBar{
+		Open:      decimal.NewFromFloat(h.Open),
+		Low:       decimal.NewFromFloat(h.Low),
+		High:      decimal.NewFromFloat(h.High),
+		Close:     decimal.NewFromFloat(h.Close),
+		AdjClose:  decimal.NewFromFloat(h.AdjClose),
+		Volume:    h.Volume,
+		Timestamp: h.Timestamp,
+	}
+	return bar.Rounded(priceHint)
+}
+
+// roundPrices rounds each of the non-nil given values to the given number of decimal places.
+func roundPrices(places int32, values ...*decimal.Decimal) {
+	for _, v := range values {
+		if v != nil {
+			*v = v.Round(places)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package dorfyn
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultDownloadConcurrency is the default number of symbols DownloadHistory fetches concurrently.
+	defaultDownloadConcurrency = 4
+)
+
+// AlignMode selects how DownloadHistory aligns the bars of the downloaded charts.
+type AlignMode int
+
+const (
+	// AlignNone leaves each chart with its own bars.
+	AlignNone AlignMode = iota
+	// AlignUnion gives all the charts the union of their timestamps. Missing bars are null bars carrying only their
+	// timestamp, timed at the session open for intervals of a day or longer.
+	AlignUnion
+	// AlignIntersection keeps only the timestamps present in all the charts.
+	AlignIntersection
+)
+
+// DownloadParams holds the options of a DownloadHistory call.
+type DownloadParams struct {
+	HistoryParams
+	// Concurrency is the maximum number of symbols fetched at once. Defaults to 4. Each symbol may itself need several
+	// concurrent requests, see GetHistory.
+	Concurrency int
+	// Align selects how the bars of the charts are aligned. Defaults to AlignNone.
+	Align AlignMode
+}
+
+// Download is the result of a DownloadHistory call.
+type Download struct {
+	// Charts maps each successfully downloaded symbol to its chart.
+	Charts map[string]*Chart
+	// Errors maps each symbol that couldn't be downloaded to the error that occurred.
+	Errors map[string]error
+	// Index holds the common timestamps of the charts, in chronological order, when they are aligned. For intervals of
+	// a day or longer, the charts are aligned on their session dates, in their exchange's time zone, as exchanges in
+	// different time zones time the same session differently, and Index holds those dates at midnight UTC.
+	Index []UnixTime
+}
+
+// DownloadHistory returns the history of each of the given symbols, as GetHistory would. A failure to download a
+// symbol doesn't fail the whole download: it's reported in the Errors of the result instead.
+func DownloadHistory(symbols []string, params DownloadParams) (*Download, error) {
+	if len(symbols) == 0 {
+		return nil, CreateArgumentError("No symbols provided to DownloadHistory")
+	}
+	if params.Start.IsZero() {
+		return nil, CreateArgumentError("No start time provided to DownloadHistory")
+	}
+
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultDownloadConcurrency
+	}
+
+	download := &Download{Charts: map[string]*Chart{}, Errors: map[string]error{}}
+	var mu sync.Mutex
+
+	forEachConcurrently(len(symbols), concurrency, func(i int) {
+		chart, err := GetHistory(symbols[i], params.HistoryParams)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			logError("Can't download history of %s: %v\n", symbols[i], err)
+			download.Errors[symbols[i]] = err
+			return
+		}
+		download.Charts[symbols[i]] = chart
+	})
+
+	if params.Align != AlignNone {
+		download.Index = alignCharts(download.Charts, params.Align, params.Interval)
+	}
+
+	return download, nil
+}
+
+// alignCharts aligns the bars of the given charts of the given interval according to mode, and returns the resulting
+// common timestamps, or session dates for intervals of a day or longer.
+func alignCharts(charts map[string]*Chart, mode AlignMode, interval Interval) []UnixTime {
+	daily := isDailyInterval(interval)
+	key := func(c *Chart, ts UnixTime) UnixTime {
+		if !daily {
+			return ts
+		}
+		y, m, d := ts.Time().In(c.Meta.Location()).Date()
+		return NewUnixTime(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+	}
+
+	// counts holds the number of charts having a bar at each key. Charts may have several bars for the same session,
+	// e.g. a live bar next to the final one, which must only be counted once.
+	counts := map[UnixTime]int{}
+	for _, c := range charts {
+		seen := map[UnixTime]bool{}
+		for _, b := range c.Bars {
+			if k := key(c, b.Timestamp); !seen[k] {
+				seen[k] = true
+				counts[k]++
+			}
+		}
+	}
+
+	var index []UnixTime
+	for ts, n := range counts {
+		if mode == AlignUnion || n == len(charts) {
+			index = append(index, ts)
+		}
+	}
+	sort.Slice(index, func(i, j int) bool { return index[i] < index[j] })
+
+	for _, c := range charts {
+		bars := make(map[UnixTime]ChartBar, len(c.Bars))
+		for _, b := range c.Bars {
+			bars[key(c, b.Timestamp)] = b
+		}
+
+		aligned := make([]ChartBar, len(index))
+		for i, k := range index {
+			if b, ok := bars[k]; ok {
+				aligned[i] = b
+				continue
+			}
+			ts := k
+			if daily {
+				ts = sessionOpen(c, k)
+			}
+			aligned[i] = ChartBar{Timestamp: ts, Null: true}
+		}
+		c.Bars = aligned
+	}
+
+	return index
+}
+
+// sessionOpen returns the time of the regular session open of the chart's exchange on the given date, itself at
+// midnight UTC. Yahoo! finance times daily bars at the session open. The time of day of the open is taken from the chart's current trading
+// period, falling back to midnight in the exchange's time zone.
+func sessionOpen(c *Chart, date UnixTime) UnixTime {
+	loc := c.Meta.Location()
+	var open time.Duration
+	if start := c.Meta.CurrentTradingPeriod.Regular.Start; start != 0 {
+		t := start.Time().In(loc)
+		open = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+
+	y, m, d := date.Time().UTC().Date()
+	return NewUnixTime(time.Date(y, m, d, 0, 0, 0, 0, loc).Add(open))
+}
+
+// isDailyInterval returns whether the bars of the given interval span a day or more. The empty interval is daily, as
+// it defaults to Interval1Day.
+func isDailyInterval(interval Interval) bool {
+	switch interval {
+	case "", Interval1Day, Interval5Day, Interval1Week, Interval1Month, Interval3Month:
+		return true
+	}
+	return false
+}
@@ -12,7 +12,10 @@ type ChartBar struct {
 	Close     decimal.Decimal
 	AdjClose  decimal.Decimal
 	Volume    int
-	Timestamp int
+	Timestamp UnixTime
+	// Null is set on the bars Yahoo! finance sends without prices, e.g. for periods without trades. Their prices are
+	// zero and must not be used; see Clean to drop or fill them.
+	Null bool
 }
 
 // OHLCHistoric is a historical quotation.
@@ -23,7 +26,7 @@ type OHLCHistoric struct {
 	Close     float64
 	AdjClose  float64
 	Volume    int
-	Timestamp int
+	Timestamp UnixTime
 }
 
 // ChartMeta is metadata associated with a chart response.
@@ -32,29 +35,30 @@ type ChartMeta struct {
 	Symbol               string    `json:"symbol" csv:"symbol"`
 	ExchangeName         string    `json:"exchangeName" csv:"exchangeName"`
 	QuoteType            QuoteType `json:"instrumentType" csv:"instrumentType"`
-	FirstTradeDate       int       `json:"firstTradeDate" csv:"firstTradeDate"`
+	FirstTradeDate       UnixTime  `json:"firstTradeDate" csv:"firstTradeDate"`
 	GMTOffset            int       `json:"GMTOffset" csv:"GMTOffset"`
 	Timezone             string    `json:"timezone" csv:"timezone"`
 	ExchangeTimezoneName string    `json:"exchangeTimezoneName" csv:"exchangeTimezoneName"`
 	ChartPreviousClose   float64   `json:"chartPreviousClose" csv:"chartPreviousClose"`
+	PriceHint            int       `json:"priceHint" csv:"priceHint"`
 	CurrentTradingPeriod struct {
 		Pre struct {
-			Timezone  string `json:"timezone" csv:"timezone"`
-			Start     int    `json:"start" csv:"start"`
-			End       int    `json:"end" csv:"end"`
-			GMTOffset int    `json:"GMTOffset" csv:"GMTOffset"`
+			Timezone  string   `json:"timezone" csv:"timezone"`
+			Start     UnixTime `json:"start" csv:"start"`
+			End       UnixTime `json:"end" csv:"end"`
+			GMTOffset int      `json:"GMTOffset" csv:"GMTOffset"`
 		} `json:"pre" csv:"pre_,inline"`
 		Regular struct {
-			Timezone  string `json:"timezone" csv:"timezone"`
-			Start     int    `json:"start" csv:"start"`
-			End       int    `json:"end" csv:"end"`
-			GMTOffset int    `json:"GMTOffset" csv:"GMTOffset"`
+			Timezone  string   `json:"timezone" csv:"timezone"`
+			Start     UnixTime `json:"start" csv:"start"`
+			End       UnixTime `json:"end" csv:"end"`
+			GMTOffset int      `json:"GMTOffset" csv:"GMTOffset"`
 		} `json:"regular" csv:"regular_,inline"`
 		Post struct {
-			Timezone  string `json:"timezone" csv:"timezone"`
-			Start     int    `json:"start" csv:"start"`
-			End       int    `json:"end" csv:"end"`
-			GMTOffset int    `json:"GMTOffset" csv:"GMTOffset"`
+			Timezone  string   `json:"timezone" csv:"timezone"`
+			Start     UnixTime `json:"start" csv:"start"`
+			End       UnixTime `json:"end" csv:"end"`
+			GMTOffset int      `json:"GMTOffset" csv:"GMTOffset"`
 		} `json:"post" csv:"post_,inline"`
 	} `json:"currentTradingPeriod" csv:"currentTradingPeriod_,inline"`
 	DataGranularity string   `json:"dataGranularity" csv:"dataGranularity"`
@@ -63,12 +67,12 @@ type ChartMeta struct {
 
 // OptionsMeta is metadata associated with an options' response.
 type OptionsMeta struct {
-	UnderlyingSymbol   string    `json:"underlyingSymbol" csv:"underlyingSymbol"`
-	ExpirationDate     int       `json:"expirationDate" csv:"expirationDate"`
-	AllExpirationDates []int     `json:"allExpirationDates" csv:"-"`
-	Strikes            []float64 `json:"strikes" csv:"-"`
-	HasMiniOptions     bool      `json:"hasMiniOptions"`
-	OldQuote           *OldQuote `json:"quote,omitempty" csv:"quote_,inline"`
+	UnderlyingSymbol   string     `json:"underlyingSymbol" csv:"underlyingSymbol"`
+	ExpirationDate     UnixTime   `json:"expirationDate" csv:"expirationDate"`
+	AllExpirationDates []UnixTime `json:"allExpirationDates" csv:"-"`
+	Strikes            []float64  `json:"strikes" csv:"-"`
+	HasMiniOptions     bool       `json:"hasMiniOptions"`
+	Quote              *Quote     `json:"quote,omitempty" csv:"quote_,inline"`
 }
 
 // Straddle is a put/call straddle for a particular strike.
@@ -80,19 +84,19 @@ type Straddle struct {
 
 // Contract is a struct containing a single option contract, usually part of a chain.
 type Contract struct {
-	Symbol            string  `json:"contractSymbol" csv:"contractSymbol"`
-	Strike            float64 `json:"strike" csv:"strike"`
-	Currency          string  `json:"currency" csv:"currency"`
-	LastPrice         float64 `json:"lastPrice" csv:"lastPrice"`
-	Change            float64 `json:"change" csv:"change"`
-	PercentChange     float64 `json:"percentChange" csv:"percentChange"`
-	Volume            int     `json:"volume" csv:"volume"`
-	OpenInterest      int     `json:"openInterest" csv:"openInterest"`
-	Bid               float64 `json:"bid" csv:"bid"`
-	Ask               float64 `json:"ask" csv:"ask"`
-	Size              string  `json:"contractSize" csv:"contractSize"`
-	Expiration        int     `json:"expiration" csv:"expiration"`
-	LastTradeDate     int     `json:"lastTradeDate" csv:"lastTradeDate"`
-	ImpliedVolatility float64 `json:"impliedVolatility" csv:"impliedVolatility"`
-	InTheMoney        bool    `json:"inTheMoney" csv:"inTheMoney"`
+	Symbol            string   `json:"contractSymbol" csv:"contractSymbol"`
+	Strike            float64  `json:"strike" csv:"strike"`
+	Currency          string   `json:"currency" csv:"currency"`
+	LastPrice         float64  `json:"lastPrice" csv:"lastPrice"`
+	Change            float64  `json:"change" csv:"change"`
+	PercentChange     float64  `json:"percentChange" csv:"percentChange"`
+	Volume            int      `json:"volume" csv:"volume"`
+	OpenInterest      int      `json:"openInterest" csv:"openInterest"`
+	Bid               float64  `json:"bid" csv:"bid"`
+	Ask               float64  `json:"ask" csv:"ask"`
+	Size              string   `json:"contractSize" csv:"contractSize"`
+	Expiration        UnixTime `json:"expiration" csv:"expiration"`
+	LastTradeDate     UnixTime `json:"lastTradeDate" csv:"lastTradeDate"`
+	ImpliedVolatility float64  `json:"impliedVolatility" csv:"impliedVolatility"`
+	InTheMoney        bool     `json:"inTheMoney" csv:"inTheMoney"`
 }
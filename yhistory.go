@@ -0,0 +1,268 @@
+package dorfyn
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxHistoryFetches is the maximum number of chart requests GetHistory runs concurrently.
+	maxHistoryFetches = 4
+)
+
+var (
+	// intervalWindows is the longest span a single chart request can cover for each intraday interval. Yahoo! finance
+	// rejects longer spans. Intervals absent from the map have no limit.
+	intervalWindows = map[Interval]time.Duration{
+		Interval1Min:  7 * 24 * time.Hour,
+		Interval2Min:  60 * 24 * time.Hour,
+		Interval5Min:  60 * 24 * time.Hour,
+		Interval15Min: 60 * 24 * time.Hour,
+		Interval30Min: 60 * 24 * time.Hour,
+		Interval90Min: 60 * 24 * time.Hour,
+		Interval60Min: 730 * 24 * time.Hour,
+		Interval1Hour: 730 * 24 * time.Hour,
+	}
+
+	// intervalDepths is how far back Yahoo! finance serves each intraday interval. Requests for older bars are rejected.
+	// Intervals absent from the map have no limit.
+	intervalDepths = map[Interval]time.Duration{
+		Interval1Min:  30 * 24 * time.Hour,
+		Interval2Min:  60 * 24 * time.Hour,
+		Interval5Min:  60 * 24 * time.Hour,
+		Interval15Min: 60 * 24 * time.Hour,
+		Interval30Min: 60 * 24 * time.Hour,
+		Interval90Min: 60 * 24 * time.Hour,
+		Interval60Min: 730 * 24 * time.Hour,
+		Interval1Hour: 730 * 24 * time.Hour,
+	}
+)
+
+// HistoryParams holds the options of a GetHistory call.
+type HistoryParams struct {
+	// Interval is the duration of the bars. Defaults to Interval1Day.
+	Interval Interval
+	// Start is the time of the first bar to return. Required.
+	Start time.Time
+	// End is the time after the last bar to return. Defaults to now.
+	End time.Time
+	// IncludePrePost includes the pre and post market bars of intraday charts.
+	IncludePrePost bool
+}
+
+// GetHistory returns the chart of the given symbol between the given start and end times. Unlike GetChart, the span
+// isn't limited by the interval: it's split into windows Yahoo! finance accepts, which are fetched concurrently and
+// stitched back into a single chart. Bars and events present in more than one window are only kept once.
+//
+// Yahoo! finance only serves a limited depth of intraday data (e.g. 30 days for one minute bars), and rejects requests
+// beyond it. The most recent window is fetched first, and Start is clipped to the depth advertised by its
+// ChartMeta.ValidRanges, or to the known depth of the interval if shorter, so the chart may start later than asked.
+func GetHistory(symbol string, params HistoryParams) (*Chart, error) {
+	if symbol == "" {
+		return nil, CreateArgumentError("No symbol provided to GetHistory")
+	}
+	if params.Start.IsZero() {
+		return nil, CreateArgumentError("No start time provided to GetHistory")
+	}
+
+	interval := params.Interval
+	if interval == "" {
+		interval = Interval1Day
+	}
+	end := params.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if !params.Start.Before(end) {
+		return nil, CreateArgumentError("Empty time range provided to GetHistory")
+	}
+
+	fetch := func(window [2]time.Time) (*Chart, error) {
+		return GetChart(symbol, &ChartParams{
+			Interval:       interval,
+			Start:          window[0],
+			End:            window[1],
+			IncludePrePost: params.IncludePrePost,
+		})
+	}
+
+	// The most recent window is always within the depth Yahoo! finance serves, and its metadata tells that depth.
+	windows := historyWindows(params.Start, end, intervalWindows[interval])
+	last := windows[len(windows)-1]
+	latest, err := fetch(last)
+	if err != nil {
+		return nil, err
+	}
+
+	start := params.Start
+	if depth := historyDepth(interval, latest.Meta.ValidRanges); depth > 0 {
+		// Keep a margin, as the depth is checked against the time Yahoo! finance receives the request.
+		if earliest := time.Now().Add(-depth + time.Hour); start.Before(earliest) {
+			logInfo("Clipping %s %s history start from %v to %v\n", symbol, interval, start, earliest)
+			start = earliest
+		}
+	}
+	if !start.Before(last[0]) {
+		return mergeCharts([]*Chart{latest}), nil
+	}
+
+	windows = historyWindows(start, last[0], intervalWindows[interval])
+	charts := make([]*Chart, len(windows)+1)
+	errs := make([]error, len(windows))
+	charts[len(windows)] = latest
+
+	forEachConcurrently(len(windows), maxHistoryFetches, func(i int) {
+		charts[i], errs[i] = fetch(windows[i])
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mergeCharts(charts), nil
+}
+
+// historyDepth returns how far back Yahoo! finance serves the given interval: the longest of the given valid ranges,
+// capped to the known depth of the interval. Zero means no limit.
+func historyDepth(interval Interval, validRanges []string) time.Duration {
+	var depth time.Duration
+	for _, r := range validRanges {
+		d := rangeDuration(r)
+		if d == 0 {
+			// "max", or a range we don't know, which may be longer than the others.
+			depth = 0
+			break
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+
+	if limit := intervalDepths[interval]; limit > 0 && (depth == 0 || limit < depth) {
+		depth = limit
+	}
+	return depth
+}
+
+// rangeDuration returns the span of the given chart range, such as "5d", "3mo" or "10y", or zero if it is "max" or
+// unknown.
+func rangeDuration(r string) time.Duration {
+	const day = 24 * time.Hour
+	if r == "ytd" {
+		now := time.Now()
+		return now.Sub(time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC))
+	}
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{{"mo", 30 * day}, {"wk", 7 * day}, {"d", day}, {"y", 365 * day}}
+	for _, u := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(r, u.suffix)); err == nil && strings.HasSuffix(r, u.suffix) && n > 0 {
+			return time.Duration(n) * u.size
+		}
+	}
+	return 0
+}
+
+// historyWindows splits the span between start and end into consecutive windows of at most the given size, as pairs
+// of start and end times. A size of zero means no limit.
+func historyWindows(start time.Time, end time.Time, size time.Duration) [][2]time.Time {
+	if size <= 0 {
+		return [][2]time.Time{{start, end}}
+	}
+
+	var windows [][2]time.Time
+	for from := start; from.Before(end); from = from.Add(size) {
+		to := from.Add(size)
+		if to.After(end) {
+			to = end
+		}
+		windows = append(windows, [2]time.Time{from, to})
+	}
+	return windows
+}
+
+// mergeCharts stitches the given charts of the same symbol, in chronological order, into a single one. The metadata
+// comes from the last chart. When several charts hold a bar with the same timestamp, the one from the latest chart
+// wins, as it's the most recent data.
+func mergeCharts(charts []*Chart) *Chart {
+	merged := &Chart{}
+	if len(charts) == 0 {
+		return merged
+	}
+	merged.Meta = charts[len(charts)-1].Meta
+
+	bars := map[UnixTime]ChartBar{}
+	dividends := map[int64]Dividend{}
+	splits := map[int64]Split{}
+	capitalGains := map[int64]CapitalGain{}
+
+	for _, c := range charts {
+		for _, b := range c.Bars {
+			bars[b.Timestamp] = b
+		}
+		for _, d := range c.Dividends {
+			dividends[d.Date.Unix()] = d
+		}
+		for _, s := range c.Splits {
+			splits[s.Date.Unix()] = s
+		}
+		for _, g := range c.CapitalGains {
+			capitalGains[g.Date.Unix()] = g
+		}
+	}
+
+	merged.Bars = make([]ChartBar, 0, len(bars))
+	for _, b := range bars {
+		merged.Bars = append(merged.Bars, b)
+	}
+	sort.Slice(merged.Bars, func(i, j int) bool { return merged.Bars[i].Timestamp < merged.Bars[j].Timestamp })
+
+	for _, d := range dividends {
+		merged.Dividends = append(merged.Dividends, d)
+	}
+	sort.Slice(merged.Dividends, func(i, j int) bool { return merged.Dividends[i].Date.Before(merged.Dividends[j].Date) })
+
+	for _, s := range splits {
+		merged.Splits = append(merged.Splits, s)
+	}
+	sort.Slice(merged.Splits, func(i, j int) bool { return merged.Splits[i].Date.Before(merged.Splits[j].Date) })
+
+	for _, g := range capitalGains {
+		merged.CapitalGains = append(merged.CapitalGains, g)
+	}
+	sort.Slice(merged.CapitalGains, func(i, j int) bool {
+		return merged.CapitalGains[i].Date.Before(merged.CapitalGains[j].Date)
+	})
+
+	return merged
+}
+
+// forEachConcurrently calls fn for each index in [0, n), running at most limit calls at once, and waits for all of
+// them to complete.
+func forEachConcurrently(n int, limit int, fn func(i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
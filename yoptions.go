@@ -0,0 +1,117 @@
+package dorfyn
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// optionsResponse is a yfin options response.
+type optionsResponse struct {
+	Inner struct {
+		Result []struct {
+			UnderlyingSymbol string     `json:"underlyingSymbol"`
+			ExpirationDates  []UnixTime `json:"expirationDates"`
+			Strikes          []float64  `json:"strikes"`
+			HasMiniOptions   bool       `json:"hasMiniOptions"`
+			Quote            *Quote     `json:"quote"`
+			Options          []struct {
+				ExpirationDate UnixTime   `json:"expirationDate"`
+				Calls          []Contract `json:"calls"`
+				Puts           []Contract `json:"puts"`
+			} `json:"options"`
+		} `json:"result"`
+		Error *yError `json:"error"`
+	} `json:"optionChain"`
+}
+
+const (
+	// yFinOptionsAPI is the path to the Yahoo! finance options API. It must be followed by a symbol.
+	yFinOptionsAPI string = "/v7/finance/options/"
+)
+
+// OptionChain is the chain of option contracts of an underlying for a single expiration date.
+type OptionChain struct {
+	Meta OptionsMeta
+	// Straddles pair the call and put of each strike, by increasing strike. Either may be missing.
+	Straddles []Straddle
+}
+
+// GetOptionChain returns the option chain of the given underlying symbol for the given expiration date, or for the
+// nearest expiration date if it's zero.
+func GetOptionChain(symbol string, expiration UnixTime) (*OptionChain, error) {
+	if symbol == "" {
+		return nil, CreateArgumentError("No symbol provided to GetOptionChain")
+	}
+
+	params := queryParams{}
+	if expiration != 0 {
+		params["date"] = strconv.FormatInt(int64(expiration), 10)
+	}
+	resp := optionsResponse{}
+
+	err := client.call(yFinOptionsAPI+url.PathEscape(symbol), params, &resp)
+	if err != nil {
+		return nil, createRemoteError(err)
+	}
+	if resp.Inner.Error != nil {
+		return nil, createRemoteError(resp.Inner.Error)
+	}
+	if len(resp.Inner.Result) == 0 {
+		return nil, createRemoteError(&yError{Code: "Not Found", Description: "No options returned for " + symbol})
+	}
+
+	result := resp.Inner.Result[0]
+	chain := &OptionChain{
+		Meta: OptionsMeta{
+			UnderlyingSymbol:   result.UnderlyingSymbol,
+			AllExpirationDates: result.ExpirationDates,
+			Strikes:            result.Strikes,
+			HasMiniOptions:     result.HasMiniOptions,
+			Quote:              result.Quote,
+		},
+	}
+	if len(result.Options) == 0 {
+		return chain, nil
+	}
+
+	options := result.Options[0]
+	chain.Meta.ExpirationDate = options.ExpirationDate
+	chain.Straddles = straddles(options.Calls, options.Puts)
+	return chain, nil
+}
+
+// Straddle returns the straddle of the given strike, or nil if the chain has no contract at that strike.
+func (chain *OptionChain) Straddle(strike float64) *Straddle {
+	i := sort.Search(len(chain.Straddles), func(i int) bool { return chain.Straddles[i].Strike >= strike })
+	if i < len(chain.Straddles) && chain.Straddles[i].Strike == strike {
+		return &chain.Straddles[i]
+	}
+	return nil
+}
+
+// straddles pairs the given calls and puts by strike, sorted by increasing strike.
+func straddles(calls []Contract, puts []Contract) []Straddle {
+	byStrike := map[float64]*Straddle{}
+	get := func(strike float64) *Straddle {
+		s, ok := byStrike[strike]
+		if !ok {
+			s = &Straddle{Strike: strike}
+			byStrike[strike] = s
+		}
+		return s
+	}
+	for i := range calls {
+		get(calls[i].Strike).Call = &calls[i]
+	}
+	for i := range puts {
+		get(puts[i].Strike).Put = &puts[i]
+	}
+
+	result := make([]Straddle, 0, len(byStrike))
+	for _, s := range byStrike {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Strike < result[j].Strike })
+	return result
+}
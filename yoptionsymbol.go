@@ -0,0 +1,156 @@
+package dorfyn
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// occDateLayout is the layout of the expiration date in an OCC option symbol.
+	occDateLayout = "060102"
+	// occSuffixLength is the length of the part of an OCC option symbol following the underlying: the expiration
+	// date, the option type and the strike.
+	occSuffixLength = 6 + 1 + 8
+	// occMaxUnderlyingLength is the maximum length of the underlying of an OCC option symbol.
+	occMaxUnderlyingLength = 6
+	// occStrikeScale is the factor between the strike and its representation in an OCC option symbol.
+	occStrikeScale = 1000
+	// occMaxStrike is the largest strike an OCC option symbol can represent.
+	occMaxStrike = 99999.999
+)
+
+// OptionSymbol is an option contract symbol in the OCC format used by Yahoo! finance, e.g. AAPL240119C00150000 for
+// the AAPL call of strike 150 expiring on January 19, 2024.
+type OptionSymbol struct {
+	// Underlying is the root symbol of the contract, usually the symbol of its underlying.
+	Underlying string
+	// Expiration is the expiration date, as midnight UTC.
+	Expiration time.Time
+	// Type is OptionTypeCall or OptionTypePut.
+	Type OptionType
+	// Strike is the strike price, with at most three decimals.
+	Strike float64
+}
+
+// NewOptionSymbol returns the option symbol with the given parts, after validating them. Only the date of expiration
+// is kept.
+func NewOptionSymbol(underlying string, expiration time.Time, optionType OptionType, strike float64) (OptionSymbol,
+	error) {
+	o := OptionSymbol{
+		Underlying: underlying,
+		Expiration: time.Date(expiration.Year(), expiration.Month(), expiration.Day(), 0, 0, 0, 0, time.UTC),
+		Type:       optionType,
+		Strike:     strike,
+	}
+	return o, o.Validate()
+}
+
+// ParseOptionSymbol parses an OCC option symbol. Both the compact form used by Yahoo! finance and the OSI form, whose
+// underlying is padded with spaces to six characters, are accepted.
+func ParseOptionSymbol(symbol string) (OptionSymbol, error) {
+	compact := strings.ReplaceAll(strings.TrimSpace(symbol), " ", "")
+	if len(compact) <= occSuffixLength {
+		return OptionSymbol{}, CreateArgumentError(fmt.Sprintf("Invalid option symbol %q: too short", symbol))
+	}
+
+	split := len(compact) - occSuffixLength
+	underlying, suffix := compact[:split], compact[split:]
+
+	expiration, err := time.Parse(occDateLayout, suffix[:6])
+	if err != nil {
+		return OptionSymbol{}, CreateArgumentError(fmt.Sprintf("Invalid option symbol %q: bad expiration date: %v",
+			symbol, err))
+	}
+
+	var optionType OptionType
+	switch suffix[6] {
+	case 'C':
+		optionType = OptionTypeCall
+	case 'P':
+		optionType = OptionTypePut
+	default:
+		return OptionSymbol{}, CreateArgumentError(fmt.Sprintf("Invalid option symbol %q: bad option type %q",
+			symbol, suffix[6]))
+	}
+
+	digits := suffix[7:]
+	if strings.Trim(digits, "0123456789") != "" {
+		return OptionSymbol{}, CreateArgumentError(fmt.Sprintf("Invalid option symbol %q: bad strike %q",
+			symbol, digits))
+	}
+	strike, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return OptionSymbol{}, CreateArgumentError(fmt.Sprintf("Invalid option symbol %q: bad strike: %v", symbol, err))
+	}
+
+	o := OptionSymbol{
+		Underlying: underlying,
+		Expiration: expiration,
+		Type:       optionType,
+		Strike:     float64(strike) / occStrikeScale,
+	}
+	if err := o.Validate(); err != nil {
+		return OptionSymbol{}, err
+	}
+	if o.String() != compact {
+		return OptionSymbol{}, CreateArgumentError(fmt.Sprintf("Invalid option symbol %q: doesn't round-trip", symbol))
+	}
+	return o, nil
+}
+
+// Validate returns an error if the option symbol can't be represented in the OCC format.
+func (o OptionSymbol) Validate() error {
+	if o.Underlying == "" || len(o.Underlying) > occMaxUnderlyingLength {
+		return CreateArgumentError(fmt.Sprintf("Invalid option underlying %q: must be 1 to %d characters",
+			o.Underlying, occMaxUnderlyingLength))
+	}
+	for _, r := range o.Underlying {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '^') {
+			return CreateArgumentError(fmt.Sprintf("Invalid option underlying %q: bad character %q", o.Underlying, r))
+		}
+	}
+	if o.Expiration.Year() < 2000 || o.Expiration.Year() > 2099 {
+		return CreateArgumentError(fmt.Sprintf("Invalid option expiration %v: out of range", o.Expiration))
+	}
+	if o.Type != OptionTypeCall && o.Type != OptionTypePut {
+		return CreateArgumentError(fmt.Sprintf("Invalid option type %q", o.Type))
+	}
+	scaled := o.Strike * occStrikeScale
+	if o.Strike <= 0 || o.Strike > occMaxStrike || math.Abs(scaled-math.Round(scaled)) > 1e-6 {
+		return CreateArgumentError(fmt.Sprintf(
+			"Invalid option strike %v: must be positive, below 100000 with at most 3 decimals", o.Strike))
+	}
+	return nil
+}
+
+// String returns the option symbol in the compact OCC format used by Yahoo! finance.
+func (o OptionSymbol) String() string {
+	letter := "C"
+	if o.Type == OptionTypePut {
+		letter = "P"
+	}
+	return fmt.Sprintf("%s%s%s%08d", o.Underlying, o.Expiration.Format(occDateLayout), letter,
+		int64(math.Round(o.Strike*occStrikeScale)))
+}
+
+// OSI returns the option symbol in the OSI format, whose underlying is padded with spaces to six characters.
+func (o OptionSymbol) OSI() string {
+	return fmt.Sprintf("%-6s", o.Underlying) + o.String()[len(o.Underlying):]
+}
+
+// OptionSymbol parses the symbol of the contract.
+func (c *Contract) OptionSymbol() (OptionSymbol, error) {
+	return ParseOptionSymbol(c.Symbol)
+}
+
+// Type returns the type of the contract, as found in its symbol.
+func (c *Contract) Type() (OptionType, error) {
+	o, err := c.OptionSymbol()
+	if err != nil {
+		return "", err
+	}
+	return o.Type, nil
+}
@@ -0,0 +1,245 @@
+package dorfyn
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// PricingModel is an option pricing model.
+type PricingModel int
+
+const (
+	// ModelBlackScholes is the Black-Scholes-Merton model, for options on stocks and indices paying a continuous
+	// dividend yield.
+	ModelBlackScholes PricingModel = iota
+	// ModelBlack76 is the Black model, for options on futures. The underlying price is the futures price.
+	ModelBlack76
+)
+
+const (
+	// expirationHour is the hour of the market close of US options on their expiration date, in expirationTimeZone.
+	expirationHour = 16
+	// expirationTimeZone is the time zone of the market close of US options.
+	expirationTimeZone = "America/New_York"
+
+	// minVolatility and maxVolatility bound the implied volatility search.
+	minVolatility = 1e-6
+	maxVolatility = 10.0
+	// volatilityTolerance is the price accuracy of the implied volatility search.
+	volatilityTolerance = 1e-8
+	// maxVolatilityIterations is the maximum number of iterations of the implied volatility search.
+	maxVolatilityIterations = 100
+)
+
+// PricingParams holds the market parameters of option pricing.
+type PricingParams struct {
+	// Model is the pricing model. Defaults to ModelBlackScholes.
+	Model PricingModel
+	// Rate is the continuously compounded risk-free interest rate, e.g. 0.05 for 5%.
+	Rate float64
+	// DividendYield is the continuously compounded dividend yield of the underlying, e.g. 0.01 for 1%. Ignored by
+	// ModelBlack76.
+	DividendYield float64
+	// Now is the valuation time. Defaults to the current time.
+	Now time.Time
+}
+
+// Greeks are the price of an option and its sensitivities.
+type Greeks struct {
+	// Price is the theoretical price of the option.
+	Price float64
+	// Delta is the change of the price for a change of 1 of the underlying price.
+	Delta float64
+	// Gamma is the change of Delta for a change of 1 of the underlying price.
+	Gamma float64
+	// Theta is the change of the price for one calendar day passing.
+	Theta float64
+	// Vega is the change of the price for a change of one percentage point of the volatility.
+	Vega float64
+	// Rho is the change of the price for a change of one percentage point of the interest rate.
+	Rho float64
+}
+
+// ContractAnalysis is the valuation of an option contract from its market price.
+type ContractAnalysis struct {
+	// MarketPrice is the price the analysis is based on: the mid price when the contract has a bid and an ask, its
+	// last price otherwise.
+	MarketPrice float64
+	// ImpliedVolatility is the volatility making the theoretical price of the contract equal to MarketPrice.
+	ImpliedVolatility float64
+	// Greeks are computed at ImpliedVolatility.
+	Greeks Greeks
+}
+
+// OptionGreeks returns the theoretical price and greeks of an option with the given type, underlying price, strike,
+// time to expiration in years and volatility.
+func OptionGreeks(optionType OptionType, underlying float64, strike float64, years float64, volatility float64,
+	params PricingParams) (Greeks, error) {
+	if err := checkPricingInputs(optionType, underlying, strike, years); err != nil {
+		return Greeks{}, err
+	}
+	if volatility <= 0 {
+		return Greeks{}, CreateArgumentError("Volatility must be positive")
+	}
+	return params.greeks(optionType, underlying, strike, years, volatility), nil
+}
+
+// ImpliedVolatility returns the volatility making the theoretical price of an option with the given type, underlying
+// price, strike and time to expiration in years equal to the given price.
+func ImpliedVolatility(optionType OptionType, price float64, underlying float64, strike float64, years float64,
+	params PricingParams) (float64, error) {
+	if err := checkPricingInputs(optionType, underlying, strike, years); err != nil {
+		return 0, err
+	}
+
+	low, high := params.greeks(optionType, underlying, strike, years, minVolatility).Price,
+		params.greeks(optionType, underlying, strike, years, maxVolatility).Price
+	if price < low-volatilityTolerance || price > high+volatilityTolerance {
+		return 0, CreateArgumentError(fmt.Sprintf("Price %v is outside of the range of theoretical prices [%v, %v]",
+			price, low, high))
+	}
+
+	// Newton-Raphson, falling back to bisection when the step leaves the bracket.
+	lowVol, highVol := minVolatility, maxVolatility
+	vol := 0.3
+	for i := 0; i < maxVolatilityIterations; i++ {
+		g := params.greeks(optionType, underlying, strike, years, vol)
+		diff := g.Price - price
+		if math.Abs(diff) < volatilityTolerance {
+			return vol, nil
+		}
+
+		if diff > 0 {
+			highVol = vol
+		} else {
+			lowVol = vol
+		}
+
+		// Vega is expressed per percentage point.
+		next := vol - diff/(g.Vega*100)
+		if g.Vega <= 0 || next <= lowVol || next >= highVol || math.IsNaN(next) {
+			next = (lowVol + highVol) / 2
+		}
+		vol = next
+	}
+
+	return 0, CreateArgumentError(fmt.Sprintf("Implied volatility search didn't converge for price %v", price))
+}
+
+// UnderlyingPrice returns the regular market price of the underlying of an options chain, as found in its metadata.
+func UnderlyingPrice(meta *OptionsMeta) (float64, error) {
+	if meta == nil || meta.Quote == nil || meta.Quote.RegularMarketPrice == nil {
+		return 0, CreateArgumentError("No underlying quote in options metadata")
+	}
+	return *meta.Quote.RegularMarketPrice, nil
+}
+
+// Analyze computes the implied volatility and greeks of the given contract from its market price, rather than relying
+// on the implied volatility reported by Yahoo! finance, which is often stale.
+func (params PricingParams) Analyze(contract *Contract, optionType OptionType, underlying float64) (ContractAnalysis,
+	error) {
+	analysis := ContractAnalysis{MarketPrice: contract.LastPrice}
+	if contract.Bid > 0 && contract.Ask >= contract.Bid {
+		analysis.MarketPrice = (contract.Bid + contract.Ask) / 2
+	}
+	if analysis.MarketPrice <= 0 {
+		return analysis, CreateArgumentError("No market price for contract " + contract.Symbol)
+	}
+
+	years := params.YearsToExpiration(contract.Expiration)
+	vol, err := ImpliedVolatility(optionType, analysis.MarketPrice, underlying, contract.Strike, years, params)
+	if err != nil {
+		logError("Can't compute the implied volatility of %s: %v\n", contract.Symbol, err)
+		return analysis, err
+	}
+
+	analysis.ImpliedVolatility = vol
+	analysis.Greeks = params.greeks(optionType, underlying, contract.Strike, years, vol)
+	return analysis, nil
+}
+
+// YearsToExpiration returns the time from the valuation time to the market close of the given expiration date, 16:00
+// in New York, in years of 365 days. It's zero for expired options.
+func (params PricingParams) YearsToExpiration(expiration UnixTime) float64 {
+	now := params.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	// Yahoo! finance times expiration dates at midnight UTC.
+	y, m, d := expiration.Time().UTC().Date()
+	offset := -5 * 60 * 60
+	expiry := time.Date(y, m, d, expirationHour, 0, 0, 0, exchangeLocation(expirationTimeZone, "EST", &offset))
+	remaining := expiry.Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining.Hours() / (365 * 24)
+}
+
+// checkPricingInputs returns an error if the given pricing inputs are invalid.
+func checkPricingInputs(optionType OptionType, underlying float64, strike float64, years float64) error {
+	if optionType != OptionTypeCall && optionType != OptionTypePut {
+		return CreateArgumentError("Unknown option type: " + string(optionType))
+	}
+	if underlying <= 0 || strike <= 0 {
+		return CreateArgumentError("Underlying price and strike must be positive")
+	}
+	if years <= 0 {
+		return CreateArgumentError("Option is expired")
+	}
+	return nil
+}
+
+// greeks returns the price and greeks of an option using the generalized Black-Scholes formula, where the cost of
+// carry is the interest rate minus the dividend yield for Black-Scholes, and zero for Black-76.
+func (params PricingParams) greeks(optionType OptionType, s float64, k float64, t float64, v float64) Greeks {
+	r := params.Rate
+	b := r - params.DividendYield
+	if params.Model == ModelBlack76 {
+		b = 0
+	}
+
+	sqrtT := math.Sqrt(t)
+	d1 := (math.Log(s/k) + (b+v*v/2)*t) / (v * sqrtT)
+	d2 := d1 - v*sqrtT
+	carry := math.Exp((b - r) * t)
+	discount := math.Exp(-r * t)
+	pdf := normPDF(d1)
+
+	g := Greeks{
+		Gamma: carry * pdf / (s * v * sqrtT),
+		Vega:  s * carry * pdf * sqrtT / 100,
+	}
+	common := -s * carry * pdf * v / (2 * sqrtT)
+
+	if optionType == OptionTypeCall {
+		g.Price = s*carry*normCDF(d1) - k*discount*normCDF(d2)
+		g.Delta = carry * normCDF(d1)
+		g.Theta = common - (b-r)*s*carry*normCDF(d1) - r*k*discount*normCDF(d2)
+		g.Rho = t * k * discount * normCDF(d2)
+	} else {
+		g.Price = k*discount*normCDF(-d2) - s*carry*normCDF(-d1)
+		g.Delta = carry * (normCDF(d1) - 1)
+		g.Theta = common + (b-r)*s*carry*normCDF(-d1) + r*k*discount*normCDF(-d2)
+		g.Rho = -t * k * discount * normCDF(-d2)
+	}
+	if params.Model == ModelBlack76 {
+		// The underlying futures price doesn't depend on the rate, so only the discounting does.
+		g.Rho = -t * g.Price
+	}
+
+	g.Theta /= 365
+	g.Rho /= 100
+	return g
+}
+
+// normCDF is the cumulative distribution function of the standard normal distribution.
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// normPDF is the probability density function of the standard normal distribution.
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
@@ -3,9 +3,9 @@ package dorfyn
 import "strings"
 
 // quoteResponse is a yfin quote quoteResponse.
-type quoteResponse struct {
+type quoteResponse[T any] struct {
 	Inner struct {
-		Result []Quote `json:"result"`
+		Result []T     `json:"result"`
 		Error  *yError `json:"error"`
 	} `json:"quoteResponse"`
 }
@@ -21,12 +21,26 @@ var (
 
 // GetQuotes returns quotes for the given symbols.
 func GetQuotes(symbols []string) ([]Quote, error) {
+	return getQuotes[Quote]("GetQuotes", symbols, nil)
+}
+
+// GetDecimalQuotes returns quotes for the given symbols, with their price, change and ratio fields decoded as decimals.
+func GetDecimalQuotes(symbols []string) ([]DecimalQuote, error) {
+	return getQuotes[DecimalQuote]("GetDecimalQuotes", symbols, nil)
+}
+
+// getQuotes fetches quotes for the given symbols and decodes them as T. caller is used in error messages, and params,
+// which may be nil, holds additional query parameters.
+func getQuotes[T any](caller string, symbols []string, params queryParams) ([]T, error) {
 	if len(symbols) == 0 {
-		return nil, CreateArgumentError("No symbols provided to GetQuotes")
+		return nil, CreateArgumentError("No symbols provided to " + caller)
 	}
 
-	params := map[string]string{"symbols": strings.Join(symbols, ",")}
-	resp := quoteResponse{}
+	if params == nil {
+		params = queryParams{}
+	}
+	params["symbols"] = strings.Join(symbols, ",")
+	resp := quoteResponse[T]{}
 
 	err := client.call(yFinQuoteAPI, params, &resp)
 	if err != nil {
@@ -121,17 +135,17 @@ type Quote struct {
 	// DisplayName is the user-friendly name of the stock or security. Applies to EQUITY quotes.
 	DisplayName *string `json:"displayName,omitempty"`
 	// DividendDate is the date when the company is expected to pay its next dividend. Applies to EQUITY, ETF and MUTUALFUND quotes.
-	DividendDate *int `json:"dividendDate,omitempty"`
+	DividendDate *UnixTime `json:"dividendDate,omitempty"`
 	// DividendRate is the amount of dividends that a company is expected to pay over the next year. Applies to MUTUALFUND quotes.
 	DividendRate *float64 `json:"dividendRate,omitempty"`
 	// DividendYield is a financial ratio that indicates how much a company pays out in dividends each year relative to its stock price. Applies to ETF and MUTUALFUND quotes.
 	DividendYield *float64 `json:"dividendYield,omitempty"`
 	// EarningsTimestamp is the timestamp of the company's earnings announcement. Applies to EQUITY quotes.
-	EarningsTimestamp *int `json:"earningsTimestamp,omitempty"`
+	EarningsTimestamp *UnixTime `json:"earningsTimestamp,omitempty"`
 	// EpsCurrentYear is the company's earnings per share (EPS) for the current year. Applies to EQUITY quotes.
-	EarningsTimestampEnd *int `json:"earningsTimestampEnd,omitempty"`
+	EarningsTimestampEnd *UnixTime `json:"earningsTimestampEnd,omitempty"`
 	// EpsForward is the company's projected earnings per share (EPS) for the next fiscal year. Applies to EQUITY quotes.
-	EarningsTimestampStart *int `json:"earningsTimestampStart,omitempty"`
+	EarningsTimestampStart *UnixTime `json:"earningsTimestampStart,omitempty"`
 	// EpsTrailingTwelveMonths is the company's earnings per share (EPS) for the past 12 months. Applies to EQUITY, ETF and MUTUALFUND quotes.
 	EpsCurrentYear *float64 `json:"epsCurrentYear,omitempty"`
 	// EpsForward is the company's projected earnings per share (EPS) for the next fiscal year. Applies to EQUITY quotes.
@@ -149,7 +163,7 @@ type Quote struct {
 	// ExchangeTimezoneShortName is the short name of the timezone of the exchange. Applies to ALL quotes.
 	ExchangeTimezoneShortName *string `json:"exchangeTimezoneShortName"`
 	// ExpireDate is the date on which the option contract expires. Applies to OPTION quotes.
-	ExpireDate *int `json:"expireDate,omitempty"`
+	ExpireDate *UnixTime `json:"expireDate,omitempty"`
 	// ExpireIsoDate is the date on which the option contract expires, in ISO 8601 format. Applies to OPTION quotes.
 	ExpireIsoDate *string `json:"expireIsoDate,omitempty"`
 	// FiftyDayAverage is the average closing price of the stock over the past 50 trading days. Applies to CRYPTOCURRENCY, CURRENCY, EQUITY, ETF, FUTURE, INDEX and MUTUALFUND quotes.
@@ -177,7 +191,7 @@ type Quote struct {
 	// FinancialCurrency is the currency in which the company reports its financial results. Applies to EQUITY, ETF and MUTUALFUND quotes.
 	FinancialCurrency *string `json:"financialCurrency,omitempty"`
 	// FirstTradeDateMilliseconds is the timestamp of the first trade of this security, in milliseconds. Applies to ALL quotes.
-	FirstTradeDateMilliseconds *int `json:"firstTradeDateMilliseconds"`
+	FirstTradeDateMilliseconds *UnixMilliTime `json:"firstTradeDateMilliseconds"`
 	// ForwardPE is the forward price-to-earnings ratio, calculated as the current share price divided by projected earnings per share for the next 12 months. Applies to EQUITY quotes.
 	ForwardPE *float64 `json:"forwardPE,omitempty"`
 	// FromCurrency is, in a currency pair, the currency that is being exchanged from. Applies to CRYPTOCURRENCY quotes.
@@ -223,7 +237,7 @@ type Quote struct {
 	// PostMarketPrice is the price of the security in post-market trading. Applies to ALL quotes.
 	PostMarketPrice *float64 `json:"postMarketPrice"`
 	// PostMarketTime is the time of the most recent post-market trade. Applies to ALL quotes.
-	PostMarketTime *int `json:"postMarketTime"`
+	PostMarketTime *UnixTime `json:"postMarketTime"`
 	// PreMarketChange is the change in the security's price in pre-market trading. Applies to ALL quotes.
 	PreMarketChange *float64 `json:"preMarketChange"`
 	// PreMarketChangePercent is the percent change in the security's price in pre-market trading. Applies to ALL quotes.
@@ -231,7 +245,7 @@ type Quote struct {
 	// PreMarketPrice is the price of the security in pre-market trading. Applies to ALL quotes.
 	PreMarketPrice *float64 `json:"preMarketPrice"`
 	// PreMarketTime is the time of the most recent pre-market trade. Applies to ALL quotes.
-	PreMarketTime *int `json:"preMarketTime"`
+	PreMarketTime *UnixTime `json:"preMarketTime"`
 	// PrevName is the name of the company prior to its most recent name change. Applies to EQUITY quotes.
 	PrevName *string `json:"prevName,omitempty"`
 	// PriceEpsCurrentYear is the price of the stock divided by the company's earnings per share (EPS) for the current year. Applies to EQUITY quotes.
@@ -263,7 +277,7 @@ type Quote struct {
 	// RegularMarketPrice is the last traded price of the security in the most recent regular trading session. Applies to ALL quotes.
 	RegularMarketPrice *float64 `json:"regularMarketPrice"`
 	// RegularMarketTime is the time of the most recent trade in the regular trading session. Applies to ALL quotes.
-	RegularMarketTime *int `json:"regularMarketTime"`
+	RegularMarketTime *UnixTime `json:"regularMarketTime"`
 	// RegularMarketVolume is the number of shares traded during the most recent regular trading session. Applies to CRYPTOCURRENCY, CURRENCY, EQUITY, ETF, FUTURE, INDEX and OPTION quotes.
 	RegularMarketVolume *int `json:"regularMarketVolume,omitempty"`
 	// SharesOutstanding is the number of shares currently held by all shareholders. Applies to EQUITY, ETF and MUTUALFUND quotes.
@@ -273,7 +287,7 @@ type Quote struct {
 	// SourceInterval is the interval at which the data source provides updates, in seconds. Applies to ALL quotes.
 	SourceInterval *int `json:"sourceInterval"`
 	// StartDate is the date on which the coin started trading. Applies to CRYPTOCURRENCY.
-	StartDate *int `json:"startDate,omitempty"`
+	StartDate *UnixTime `json:"startDate,omitempty"`
 	// Strike is the strike price of an options contract, which is the price at which the contract can be exercised. Applies to OPTION quotes.
 	Strike *float64 `json:"strike,omitempty"`
 	// Symbol is the ticker symbol of the security. Applies to ALL quotes.
@@ -0,0 +1,647 @@
+package dorfyn
+
+// QuoteView is a view of a Quote that only exposes the fields applicable to its QuoteType. The concrete type of a
+// QuoteView is one of *EquityQuote, *ETFQuote, *MutualFundQuote, *IndexQuote, *ForexQuote, *CryptoQuote,
+// *FutureQuote or *OptionQuote, or *BaseQuote for quote types dorfyn doesn't know about, so it's meant to be used in a
+// type switch:
+//
+//	switch v := quote.View().(type) {
+//	case *dorfyn.EquityQuote:
+//		if v.TrailingPE != nil {
+//			fmt.Println(*v.Symbol, *v.TrailingPE)
+//		}
+//	case *dorfyn.CryptoQuote:
+//		if v.CirculatingSupply != nil {
+//			fmt.Println(*v.Symbol, *v.CirculatingSupply)
+//		}
+//	}
+//
+// The fields of the views point to the same values as the fields of the Quote they were built from, and are nil when
+// Yahoo! finance doesn't provide them. Refer to Quote for the documentation of each field.
+type QuoteView interface {
+	// Base returns the fields shared by all quote types.
+	Base() *BaseQuote
+}
+
+// BaseQuote holds the fields that apply to ALL quotes.
+type BaseQuote struct {
+	Currency                      *string        `json:"currency"`
+	CustomPriceAlertConfidence    *string        `json:"customPriceAlertConfidence"`
+	EsgPopulated                  *bool          `json:"esgPopulated"`
+	Exchange                      *string        `json:"exchange"`
+	ExchangeDataDelayedBy         *int           `json:"exchangeDataDelayedBy"`
+	ExchangeTimezoneName          *string        `json:"exchangeTimezoneName"`
+	ExchangeTimezoneShortName     *string        `json:"exchangeTimezoneShortName"`
+	FiftyTwoWeekHigh              *float64       `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekHighChange        *float64       `json:"fiftyTwoWeekHighChange"`
+	FiftyTwoWeekHighChangePercent *float64       `json:"fiftyTwoWeekHighChangePercent"`
+	FiftyTwoWeekLow               *float64       `json:"fiftyTwoWeekLow"`
+	FiftyTwoWeekLowChange         *float64       `json:"fiftyTwoWeekLowChange"`
+	FiftyTwoWeekLowChangePercent  *float64       `json:"fiftyTwoWeekLowChangePercent"`
+	FiftyTwoWeekRange             *string        `json:"fiftyTwoWeekRange"`
+	FirstTradeDateMilliseconds    *UnixMilliTime `json:"firstTradeDateMilliseconds"`
+	FullExchangeName              *string        `json:"fullExchangeName"`
+	GmtOffSetMilliseconds         *int           `json:"gmtOffSetMilliseconds"`
+	Language                      *string        `json:"language"`
+	Market                        *string        `json:"market"`
+	MarketState                   *MarketState   `json:"marketState,omitempty"`
+	PostMarketChange              *float64       `json:"postMarketChange"`
+	PostMarketChangePercent       *float64       `json:"postMarketChangePercent"`
+	PostMarketPrice               *float64       `json:"postMarketPrice"`
+	PostMarketTime                *UnixTime      `json:"postMarketTime"`
+	PreMarketChange               *float64       `json:"preMarketChange"`
+	PreMarketChangePercent        *float64       `json:"preMarketChangePercent"`
+	PreMarketPrice                *float64       `json:"preMarketPrice"`
+	PreMarketTime                 *UnixTime      `json:"preMarketTime"`
+	PriceHint                     *int           `json:"priceHint"`
+	QuoteSourceName               *string        `json:"quoteSourceName"`
+	QuoteType                     *QuoteType     `json:"quoteType"`
+	Region                        *string        `json:"region"`
+	RegularMarketChange           *float64       `json:"regularMarketChange"`
+	RegularMarketChangePercent    *float64       `json:"regularMarketChangePercent"`
+	RegularMarketPreviousClose    *float64       `json:"regularMarketPreviousClose"`
+	RegularMarketPrice            *float64       `json:"regularMarketPrice"`
+	RegularMarketTime             *UnixTime      `json:"regularMarketTime"`
+	ShortName                     *string        `json:"shortName"`
+	SourceInterval                *int           `json:"sourceInterval"`
+	Symbol                        *string        `json:"symbol"`
+	Tradeable                     *bool          `json:"tradeable"`
+	Triggerable                   *bool          `json:"triggerable"`
+	TypeDisp                      *string        `json:"typeDisp"`
+}
+
+// EquityQuote is the view of a quote for an equity.
+type EquityQuote struct {
+	BaseQuote
+
+	Ask                               *float64  `json:"ask,omitempty"`
+	AskSize                           *float64  `json:"askSize,omitempty"`
+	AverageAnalystRating              *string   `json:"averageAnalystRating,omitempty"`
+	AverageDailyVolume10Day           *int      `json:"averageDailyVolume10Day,omitempty"`
+	AverageDailyVolume3Month          *int      `json:"averageDailyVolume3Month,omitempty"`
+	Bid                               *float64  `json:"bid,omitempty"`
+	BidSize                           *int      `json:"bidSize,omitempty"`
+	BookValue                         *float64  `json:"bookValue,omitempty"`
+	DisplayName                       *string   `json:"displayName,omitempty"`
+	DividendDate                      *UnixTime `json:"dividendDate,omitempty"`
+	EarningsTimestamp                 *UnixTime `json:"earningsTimestamp,omitempty"`
+	EarningsTimestampEnd              *UnixTime `json:"earningsTimestampEnd,omitempty"`
+	EarningsTimestampStart            *UnixTime `json:"earningsTimestampStart,omitempty"`
+	EpsCurrentYear                    *float64  `json:"epsCurrentYear,omitempty"`
+	EpsForward                        *float64  `json:"epsForward,omitempty"`
+	EpsTrailingTwelveMonths           *float64  `json:"epsTrailingTwelveMonths,omitempty"`
+	FiftyDayAverage                   *float64  `json:"fiftyDayAverage,omitempty"`
+	FiftyDayAverageChange             *float64  `json:"fiftyDayAverageChange,omitempty"`
+	FiftyDayAverageChangePercent      *float64  `json:"fiftyDayAverageChangePercent,omitempty"`
+	FiftyTwoWeekChangePercent         *float64  `json:"fiftyTwoWeekChangePercent,omitempty"`
+	FinancialCurrency                 *string   `json:"financialCurrency,omitempty"`
+	ForwardPE                         *float64  `json:"forwardPE,omitempty"`
+	IpoExpectedDate                   *string   `json:"ipoExpectedDate,omitempty"`
+	LongName                          *string   `json:"longName,omitempty"`
+	MarketCap                         *int      `json:"marketCap,omitempty"`
+	MessageBoardId                    *string   `json:"messageBoardId,omitempty"`
+	NameChangeDate                    *string   `json:"nameChangeDate,omitempty"`
+	PrevName                          *string   `json:"prevName,omitempty"`
+	PriceEpsCurrentYear               *float64  `json:"priceEpsCurrentYear,omitempty"`
+	PriceToBook                       *float64  `json:"priceToBook,omitempty"`
+	RegularMarketDayHigh              *float64  `json:"regularMarketDayHigh,omitempty"`
+	RegularMarketDayLow               *float64  `json:"regularMarketDayLow,omitempty"`
+	RegularMarketDayRange             *string   `json:"regularMarketDayRange,omitempty"`
+	RegularMarketOpen                 *float64  `json:"regularMarketOpen,omitempty"`
+	RegularMarketVolume               *int      `json:"regularMarketVolume,omitempty"`
+	SharesOutstanding                 *int      `json:"sharesOutstanding,omitempty"`
+	TrailingAnnualDividendRate        *float64  `json:"trailingAnnualDividendRate,omitempty"`
+	TrailingAnnualDividendYield       *float64  `json:"trailingAnnualDividendYield,omitempty"`
+	TrailingPE                        *float64  `json:"trailingPE,omitempty"`
+	TwoHundredDayAverage              *float64  `json:"twoHundredDayAverage,omitempty"`
+	TwoHundredDayAverageChange        *float64  `json:"twoHundredDayAverageChange,omitempty"`
+	TwoHundredDayAverageChangePercent *float64  `json:"twoHundredDayAverageChangePercent,omitempty"`
+}
+
+// ETFQuote is the view of a quote for an exchange-traded fund.
+type ETFQuote struct {
+	BaseQuote
+
+	Ask                               *float64  `json:"ask,omitempty"`
+	AskSize                           *float64  `json:"askSize,omitempty"`
+	AverageDailyVolume10Day           *int      `json:"averageDailyVolume10Day,omitempty"`
+	AverageDailyVolume3Month          *int      `json:"averageDailyVolume3Month,omitempty"`
+	Bid                               *float64  `json:"bid,omitempty"`
+	BidSize                           *int      `json:"bidSize,omitempty"`
+	BookValue                         *float64  `json:"bookValue,omitempty"`
+	DividendDate                      *UnixTime `json:"dividendDate,omitempty"`
+	DividendYield                     *float64  `json:"dividendYield,omitempty"`
+	EpsCurrentYear                    *float64  `json:"epsCurrentYear,omitempty"`
+	EpsTrailingTwelveMonths           *float64  `json:"epsTrailingTwelveMonths,omitempty"`
+	FiftyDayAverage                   *float64  `json:"fiftyDayAverage,omitempty"`
+	FiftyDayAverageChange             *float64  `json:"fiftyDayAverageChange,omitempty"`
+	FiftyDayAverageChangePercent      *float64  `json:"fiftyDayAverageChangePercent,omitempty"`
+	FiftyTwoWeekChangePercent         *float64  `json:"fiftyTwoWeekChangePercent,omitempty"`
+	FinancialCurrency                 *string   `json:"financialCurrency,omitempty"`
+	LongName                          *string   `json:"longName,omitempty"`
+	MarketCap                         *int      `json:"marketCap,omitempty"`
+	MessageBoardId                    *string   `json:"messageBoardId,omitempty"`
+	NetAssets                         *float64  `json:"netAssets,omitempty"`
+	NetExpenseRatio                   *float64  `json:"netExpenseRatio,omitempty"`
+	PriceToBook                       *float64  `json:"priceToBook,omitempty"`
+	RegularMarketDayHigh              *float64  `json:"regularMarketDayHigh,omitempty"`
+	RegularMarketDayLow               *float64  `json:"regularMarketDayLow,omitempty"`
+	RegularMarketDayRange             *string   `json:"regularMarketDayRange,omitempty"`
+	RegularMarketOpen                 *float64  `json:"regularMarketOpen,omitempty"`
+	RegularMarketVolume               *int      `json:"regularMarketVolume,omitempty"`
+	SharesOutstanding                 *int      `json:"sharesOutstanding,omitempty"`
+	TrailingAnnualDividendRate        *float64  `json:"trailingAnnualDividendRate,omitempty"`
+	TrailingAnnualDividendYield       *float64  `json:"trailingAnnualDividendYield,omitempty"`
+	TrailingPE                        *float64  `json:"trailingPE,omitempty"`
+	TrailingThreeMonthNavReturns      *float64  `json:"trailingThreeMonthNavReturns,omitempty"`
+	TrailingThreeMonthReturns         *float64  `json:"trailingThreeMonthReturns,omitempty"`
+	TwoHundredDayAverage              *float64  `json:"twoHundredDayAverage,omitempty"`
+	TwoHundredDayAverageChange        *float64  `json:"twoHundredDayAverageChange,omitempty"`
+	TwoHundredDayAverageChangePercent *float64  `json:"twoHundredDayAverageChangePercent,omitempty"`
+	YtdReturn                         *float64  `json:"ytdReturn,omitempty"`
+}
+
+// MutualFundQuote is the view of a quote for a mutual fund.
+type MutualFundQuote struct {
+	BaseQuote
+
+	AverageDailyVolume10Day           *int      `json:"averageDailyVolume10Day,omitempty"`
+	AverageDailyVolume3Month          *int      `json:"averageDailyVolume3Month,omitempty"`
+	BookValue                         *float64  `json:"bookValue,omitempty"`
+	DividendDate                      *UnixTime `json:"dividendDate,omitempty"`
+	DividendRate                      *float64  `json:"dividendRate,omitempty"`
+	DividendYield                     *float64  `json:"dividendYield,omitempty"`
+	EpsCurrentYear                    *float64  `json:"epsCurrentYear,omitempty"`
+	EpsTrailingTwelveMonths           *float64  `json:"epsTrailingTwelveMonths,omitempty"`
+	FiftyDayAverage                   *float64  `json:"fiftyDayAverage,omitempty"`
+	FiftyDayAverageChange             *float64  `json:"fiftyDayAverageChange,omitempty"`
+	FiftyDayAverageChangePercent      *float64  `json:"fiftyDayAverageChangePercent,omitempty"`
+	FiftyTwoWeekChangePercent         *float64  `json:"fiftyTwoWeekChangePercent,omitempty"`
+	FinancialCurrency                 *string   `json:"financialCurrency,omitempty"`
+	LongName                          *string   `json:"longName,omitempty"`
+	MarketCap                         *int      `json:"marketCap,omitempty"`
+	MessageBoardId                    *string   `json:"messageBoardId,omitempty"`
+	NetAssets                         *float64  `json:"netAssets,omitempty"`
+	NetExpenseRatio                   *float64  `json:"netExpenseRatio,omitempty"`
+	PriceToBook                       *float64  `json:"priceToBook,omitempty"`
+	SharesOutstanding                 *int      `json:"sharesOutstanding,omitempty"`
+	TrailingAnnualDividendRate        *float64  `json:"trailingAnnualDividendRate,omitempty"`
+	TrailingAnnualDividendYield       *float64  `json:"trailingAnnualDividendYield,omitempty"`
+	TrailingPE                        *float64  `json:"trailingPE,omitempty"`
+	TrailingThreeMonthReturns         *float64  `json:"trailingThreeMonthReturns,omitempty"`
+	TwoHundredDayAverage              *float64  `json:"twoHundredDayAverage,omitempty"`
+	TwoHundredDayAverageChange        *float64  `json:"twoHundredDayAverageChange,omitempty"`
+	TwoHundredDayAverageChangePercent *float64  `json:"twoHundredDayAverageChangePercent,omitempty"`
+	YtdReturn                         *float64  `json:"ytdReturn,omitempty"`
+}
+
+// IndexQuote is the view of a quote for an index.
+type IndexQuote struct {
+	BaseQuote
+
+	Ask                               *float64 `json:"ask,omitempty"`
+	AskSize                           *float64 `json:"askSize,omitempty"`
+	AverageDailyVolume10Day           *int     `json:"averageDailyVolume10Day,omitempty"`
+	AverageDailyVolume3Month          *int     `json:"averageDailyVolume3Month,omitempty"`
+	Bid                               *float64 `json:"bid,omitempty"`
+	BidSize                           *int     `json:"bidSize,omitempty"`
+	FiftyDayAverage                   *float64 `json:"fiftyDayAverage,omitempty"`
+	FiftyDayAverageChange             *float64 `json:"fiftyDayAverageChange,omitempty"`
+	FiftyDayAverageChangePercent      *float64 `json:"fiftyDayAverageChangePercent,omitempty"`
+	FiftyTwoWeekChangePercent         *float64 `json:"fiftyTwoWeekChangePercent,omitempty"`
+	LongName                          *string  `json:"longName,omitempty"`
+	MessageBoardId                    *string  `json:"messageBoardId,omitempty"`
+	RegularMarketDayHigh              *float64 `json:"regularMarketDayHigh,omitempty"`
+	RegularMarketDayLow               *float64 `json:"regularMarketDayLow,omitempty"`
+	RegularMarketDayRange             *string  `json:"regularMarketDayRange,omitempty"`
+	RegularMarketOpen                 *float64 `json:"regularMarketOpen,omitempty"`
+	RegularMarketVolume               *int     `json:"regularMarketVolume,omitempty"`
+	TwoHundredDayAverage              *float64 `json:"twoHundredDayAverage,omitempty"`
+	TwoHundredDayAverageChange        *float64 `json:"twoHundredDayAverageChange,omitempty"`
+	TwoHundredDayAverageChangePercent *float64 `json:"twoHundredDayAverageChangePercent,omitempty"`
+}
+
+// ForexQuote is the view of a quote for a forex pair.
+type ForexQuote struct {
+	BaseQuote
+
+	Ask                               *float64 `json:"ask,omitempty"`
+	AskSize                           *float64 `json:"askSize,omitempty"`
+	AverageDailyVolume10Day           *int     `json:"averageDailyVolume10Day,omitempty"`
+	AverageDailyVolume3Month          *int     `json:"averageDailyVolume3Month,omitempty"`
+	Bid                               *float64 `json:"bid,omitempty"`
+	BidSize                           *int     `json:"bidSize,omitempty"`
+	FiftyDayAverage                   *float64 `json:"fiftyDayAverage,omitempty"`
+	FiftyDayAverageChange             *float64 `json:"fiftyDayAverageChange,omitempty"`
+	FiftyDayAverageChangePercent      *float64 `json:"fiftyDayAverageChangePercent,omitempty"`
+	FiftyTwoWeekChangePercent         *float64 `json:"fiftyTwoWeekChangePercent,omitempty"`
+	LongName                          *string  `json:"longName,omitempty"`
+	MessageBoardId                    *string  `json:"messageBoardId,omitempty"`
+	RegularMarketDayHigh              *float64 `json:"regularMarketDayHigh,omitempty"`
+	RegularMarketDayLow               *float64 `json:"regularMarketDayLow,omitempty"`
+	RegularMarketDayRange             *string  `json:"regularMarketDayRange,omitempty"`
+	RegularMarketOpen                 *float64 `json:"regularMarketOpen,omitempty"`
+	RegularMarketVolume               *int     `json:"regularMarketVolume,omitempty"`
+	TwoHundredDayAverage              *float64 `json:"twoHundredDayAverage,omitempty"`
+	TwoHundredDayAverageChange        *float64 `json:"twoHundredDayAverageChange,omitempty"`
+	TwoHundredDayAverageChangePercent *float64 `json:"twoHundredDayAverageChangePercent,omitempty"`
+}
+
+// CryptoQuote is the view of a quote for a crypto pair.
+type CryptoQuote struct {
+	BaseQuote
+
+	AverageDailyVolume10Day           *int      `json:"averageDailyVolume10Day,omitempty"`
+	AverageDailyVolume3Month          *int      `json:"averageDailyVolume3Month,omitempty"`
+	CirculatingSupply                 *int      `json:"circulatingSupply,omitempty"`
+	CoinImageUrl                      *string   `json:"coinImageUrl,omitempty"`
+	CoinMarketCapLink                 *string   `json:"coinMarketCapLink,omitempty"`
+	CryptoTradeable                   *bool     `json:"cryptoTradeable,omitempty"`
+	FiftyDayAverage                   *float64  `json:"fiftyDayAverage,omitempty"`
+	FiftyDayAverageChange             *float64  `json:"fiftyDayAverageChange,omitempty"`
+	FiftyDayAverageChangePercent      *float64  `json:"fiftyDayAverageChangePercent,omitempty"`
+	FiftyTwoWeekChangePercent         *float64  `json:"fiftyTwoWeekChangePercent,omitempty"`
+	FromCurrency                      *string   `json:"fromCurrency,omitempty"`
+	LastMarket                        *string   `json:"lastMarket,omitempty"`
+	LogoUrl                           *string   `json:"logoUrl,omitempty"`
+	LongName                          *string   `json:"longName,omitempty"`
+	MarketCap                         *int      `json:"marketCap,omitempty"`
+	MessageBoardId                    *string   `json:"messageBoardId,omitempty"`
+	RegularMarketDayHigh              *float64  `json:"regularMarketDayHigh,omitempty"`
+	RegularMarketDayLow               *float64  `json:"regularMarketDayLow,omitempty"`
+	RegularMarketDayRange             *string   `json:"regularMarketDayRange,omitempty"`
+	RegularMarketOpen                 *float64  `json:"regularMarketOpen,omitempty"`
+	RegularMarketVolume               *int      `json:"regularMarketVolume,omitempty"`
+	StartDate                         *UnixTime `json:"startDate,omitempty"`
+	ToCurrency                        *string   `json:"toCurrency,omitempty"`
+	TwoHundredDayAverage              *float64  `json:"twoHundredDayAverage,omitempty"`
+	TwoHundredDayAverageChange        *float64  `json:"twoHundredDayAverageChange,omitempty"`
+	TwoHundredDayAverageChangePercent *float64  `json:"twoHundredDayAverageChangePercent,omitempty"`
+	Volume24Hr                        *int      `json:"volume24Hr,omitempty"`
+	VolumeAllCurrencies               *int      `json:"volumeAllCurrencies,omitempty"`
+}
+
+// FutureQuote is the view of a quote for a futures contract.
+type FutureQuote struct {
+	BaseQuote
+
+	Ask                               *float64 `json:"ask,omitempty"`
+	AverageDailyVolume10Day           *int     `json:"averageDailyVolume10Day,omitempty"`
+	AverageDailyVolume3Month          *int     `json:"averageDailyVolume3Month,omitempty"`
+	Bid                               *float64 `json:"bid,omitempty"`
+	ContractSymbol                    *bool    `json:"contractSymbol,omitempty"`
+	FiftyDayAverage                   *float64 `json:"fiftyDayAverage,omitempty"`
+	FiftyDayAverageChange             *float64 `json:"fiftyDayAverageChange,omitempty"`
+	FiftyDayAverageChangePercent      *float64 `json:"fiftyDayAverageChangePercent,omitempty"`
+	FiftyTwoWeekChangePercent         *float64 `json:"fiftyTwoWeekChangePercent,omitempty"`
+	OpenInterest                      *int     `json:"openInterest,omitempty"`
+	RegularMarketDayHigh              *float64 `json:"regularMarketDayHigh,omitempty"`
+	RegularMarketDayLow               *float64 `json:"regularMarketDayLow,omitempty"`
+	RegularMarketDayRange             *string  `json:"regularMarketDayRange,omitempty"`
+	RegularMarketOpen                 *float64 `json:"regularMarketOpen,omitempty"`
+	RegularMarketVolume               *int     `json:"regularMarketVolume,omitempty"`
+	TwoHundredDayAverage              *float64 `json:"twoHundredDayAverage,omitempty"`
+	TwoHundredDayAverageChange        *float64 `json:"twoHundredDayAverageChange,omitempty"`
+	TwoHundredDayAverageChangePercent *float64 `json:"twoHundredDayAverageChangePercent,omitempty"`
+	UnderlyingExchangeSymbol          *string  `json:"underlyingExchangeSymbol,omitempty"`
+	UnderlyingSymbol                  *string  `json:"underlyingSymbol,omitempty"`
+}
+
+// OptionQuote is the view of a quote for an option contract.
+type OptionQuote struct {
+	BaseQuote
+
+	Ask                   *float64    `json:"ask,omitempty"`
+	Bid                   *float64    `json:"bid,omitempty"`
+	ExpireDate            *UnixTime   `json:"expireDate,omitempty"`
+	ExpireIsoDate         *string     `json:"expireIsoDate,omitempty"`
+	HeadSymbolAsString    *string     `json:"headSymbolAsString,omitempty"`
+	OpenInterest          *int        `json:"openInterest,omitempty"`
+	OptionsType           *OptionType `json:"optionsType,omitempty"`
+	RegularMarketDayHigh  *float64    `json:"regularMarketDayHigh,omitempty"`
+	RegularMarketDayLow   *float64    `json:"regularMarketDayLow,omitempty"`
+	RegularMarketDayRange *string     `json:"regularMarketDayRange,omitempty"`
+	RegularMarketOpen     *float64    `json:"regularMarketOpen,omitempty"`
+	RegularMarketVolume   *int        `json:"regularMarketVolume,omitempty"`
+	Strike                *float64    `json:"strike,omitempty"`
+	UnderlyingShortName   *string     `json:"underlyingShortName,omitempty"`
+	UnderlyingSymbol      *string     `json:"underlyingSymbol,omitempty"`
+}
+
+// Base returns the fields shared by all quote types.
+func (b *BaseQuote) Base() *BaseQuote {
+	return b
+}
+
+// View returns a view of the quote that only exposes the fields applicable to its QuoteType.
+func (q *Quote) View() QuoteView {
+	base := q.base()
+	if q.QuoteType == nil {
+		return &base
+	}
+
+	switch *q.QuoteType {
+	case QuoteTypeEquity:
+		return &EquityQuote{
+			BaseQuote:                         base,
+			Ask:                               q.Ask,
+			AskSize:                           q.AskSize,
+			AverageAnalystRating:              q.AverageAnalystRating,
+			AverageDailyVolume10Day:           q.AverageDailyVolume10Day,
+			AverageDailyVolume3Month:          q.AverageDailyVolume3Month,
+			Bid:                               q.Bid,
+			BidSize:                           q.BidSize,
+			BookValue:                         q.BookValue,
+			DisplayName:                       q.DisplayName,
+			DividendDate:                      q.DividendDate,
+			EarningsTimestamp:                 q.EarningsTimestamp,
+			EarningsTimestampEnd:              q.EarningsTimestampEnd,
+			EarningsTimestampStart:            q.EarningsTimestampStart,
+			EpsCurrentYear:                    q.EpsCurrentYear,
+			EpsForward:                        q.EpsForward,
+			EpsTrailingTwelveMonths:           q.EpsTrailingTwelveMonths,
+			FiftyDayAverage:                   q.FiftyDayAverage,
+			FiftyDayAverageChange:             q.FiftyDayAverageChange,
+			FiftyDayAverageChangePercent:      q.FiftyDayAverageChangePercent,
+			FiftyTwoWeekChangePercent:         q.FiftyTwoWeekChangePercent,
+			FinancialCurrency:                 q.FinancialCurrency,
+			ForwardPE:                         q.ForwardPE,
+			IpoExpectedDate:                   q.IpoExpectedDate,
+			LongName:                          q.LongName,
+			MarketCap:                         q.MarketCap,
+			MessageBoardId:                    q.MessageBoardId,
+			NameChangeDate:                    q.NameChangeDate,
+			PrevName:                          q.PrevName,
+			PriceEpsCurrentYear:               q.PriceEpsCurrentYear,
+			PriceToBook:                       q.PriceToBook,
+			RegularMarketDayHigh:              q.RegularMarketDayHigh,
+			RegularMarketDayLow:               q.RegularMarketDayLow,
+			RegularMarketDayRange:             q.RegularMarketDayRange,
+			RegularMarketOpen:                 q.RegularMarketOpen,
+			RegularMarketVolume:               q.RegularMarketVolume,
+			SharesOutstanding:                 q.SharesOutstanding,
+			TrailingAnnualDividendRate:        q.TrailingAnnualDividendRate,
+			TrailingAnnualDividendYield:       q.TrailingAnnualDividendYield,
+			TrailingPE:                        q.TrailingPE,
+			TwoHundredDayAverage:              q.TwoHundredDayAverage,
+			TwoHundredDayAverageChange:        q.TwoHundredDayAverageChange,
+			TwoHundredDayAverageChangePercent: q.TwoHundredDayAverageChangePercent,
+		}
+	case QuoteTypeETF:
+		return &ETFQuote{
+			BaseQuote:                         base,
+			Ask:                               q.Ask,
+			AskSize:                           q.AskSize,
+			AverageDailyVolume10Day:           q.AverageDailyVolume10Day,
+			AverageDailyVolume3Month:          q.AverageDailyVolume3Month,
+			Bid:                               q.Bid,
+			BidSize:                           q.BidSize,
+			BookValue:                         q.BookValue,
+			DividendDate:                      q.DividendDate,
+			DividendYield:                     q.DividendYield,
+			EpsCurrentYear:                    q.EpsCurrentYear,
+			EpsTrailingTwelveMonths:           q.EpsTrailingTwelveMonths,
+			FiftyDayAverage:                   q.FiftyDayAverage,
+			FiftyDayAverageChange:             q.FiftyDayAverageChange,
+			FiftyDayAverageChangePercent:      q.FiftyDayAverageChangePercent,
+			FiftyTwoWeekChangePercent:         q.FiftyTwoWeekChangePercent,
+			FinancialCurrency:                 q.FinancialCurrency,
+			LongName:                          q.LongName,
+			MarketCap:                         q.MarketCap,
+			MessageBoardId:                    q.MessageBoardId,
+			NetAssets:                         q.NetAssets,
+			NetExpenseRatio:                   q.NetExpenseRatio,
+			PriceToBook:                       q.PriceToBook,
+			RegularMarketDayHigh:              q.RegularMarketDayHigh,
+			RegularMarketDayLow:               q.RegularMarketDayLow,
+			RegularMarketDayRange:             q.RegularMarketDayRange,
+			RegularMarketOpen:                 q.RegularMarketOpen,
+			RegularMarketVolume:               q.RegularMarketVolume,
+			SharesOutstanding:                 q.SharesOutstanding,
+			TrailingAnnualDividendRate:        q.TrailingAnnualDividendRate,
+			TrailingAnnualDividendYield:       q.TrailingAnnualDividendYield,
+			TrailingPE:                        q.TrailingPE,
+			TrailingThreeMonthNavReturns:      q.TrailingThreeMonthNavReturns,
+			TrailingThreeMonthReturns:         q.TrailingThreeMonthReturns,
+			TwoHundredDayAverage:              q.TwoHundredDayAverage,
+			TwoHundredDayAverageChange:        q.TwoHundredDayAverageChange,
+			TwoHundredDayAverageChangePercent: q.TwoHundredDayAverageChangePercent,
+			YtdReturn:                         q.YtdReturn,
+		}
+	case QuoteTypeMutualFund:
+		return &MutualFundQuote{
+			BaseQuote:                         base,
+			AverageDailyVolume10Day:           q.AverageDailyVolume10Day,
+			AverageDailyVolume3Month:          q.AverageDailyVolume3Month,
+			BookValue:                         q.BookValue,
+			DividendDate:                      q.DividendDate,
+			DividendRate:                      q.DividendRate,
+			DividendYield:                     q.DividendYield,
+			EpsCurrentYear:                    q.EpsCurrentYear,
+			EpsTrailingTwelveMonths:           q.EpsTrailingTwelveMonths,
+			FiftyDayAverage:                   q.FiftyDayAverage,
+			FiftyDayAverageChange:             q.FiftyDayAverageChange,
+			FiftyDayAverageChangePercent:      q.FiftyDayAverageChangePercent,
+			FiftyTwoWeekChangePercent:         q.FiftyTwoWeekChangePercent,
+			FinancialCurrency:                 q.FinancialCurrency,
+			LongName:                          q.LongName,
+			MarketCap:                         q.MarketCap,
+			MessageBoardId:                    q.MessageBoardId,
+			NetAssets:                         q.NetAssets,
+			NetExpenseRatio:                   q.NetExpenseRatio,
+			PriceToBook:                       q.PriceToBook,
+			SharesOutstanding:                 q.SharesOutstanding,
+			TrailingAnnualDividendRate:        q.TrailingAnnualDividendRate,
+			TrailingAnnualDividendYield:       q.TrailingAnnualDividendYield,
+			TrailingPE:                        q.TrailingPE,
+			TrailingThreeMonthReturns:         q.TrailingThreeMonthReturns,
+			TwoHundredDayAverage:              q.TwoHundredDayAverage,
+			TwoHundredDayAverageChange:        q.TwoHundredDayAverageChange,
+			TwoHundredDayAverageChangePercent: q.TwoHundredDayAverageChangePercent,
+			YtdReturn:                         q.YtdReturn,
+		}
+	case QuoteTypeIndex:
+		return &IndexQuote{
+			BaseQuote:                         base,
+			Ask:                               q.Ask,
+			AskSize:                           q.AskSize,
+			AverageDailyVolume10Day:           q.AverageDailyVolume10Day,
+			AverageDailyVolume3Month:          q.AverageDailyVolume3Month,
+			Bid:                               q.Bid,
+			BidSize:                           q.BidSize,
+			FiftyDayAverage:                   q.FiftyDayAverage,
+			FiftyDayAverageChange:             q.FiftyDayAverageChange,
+			FiftyDayAverageChangePercent:      q.FiftyDayAverageChangePercent,
+			FiftyTwoWeekChangePercent:         q.FiftyTwoWeekChangePercent,
+			LongName:                          q.LongName,
+			MessageBoardId:                    q.MessageBoardId,
+			RegularMarketDayHigh:              q.RegularMarketDayHigh,
+			RegularMarketDayLow:               q.RegularMarketDayLow,
+			RegularMarketDayRange:             q.RegularMarketDayRange,
+			RegularMarketOpen:                 q.RegularMarketOpen,
+			RegularMarketVolume:               q.RegularMarketVolume,
+			TwoHundredDayAverage:              q.TwoHundredDayAverage,
+			TwoHundredDayAverageChange:        q.TwoHundredDayAverageChange,
+			TwoHundredDayAverageChangePercent: q.TwoHundredDayAverageChangePercent,
+		}
+	case QuoteTypeForexPair:
+		return &ForexQuote{
+			BaseQuote:                         base,
+			Ask:                               q.Ask,
+			AskSize:                           q.AskSize,
+			AverageDailyVolume10Day:           q.AverageDailyVolume10Day,
+			AverageDailyVolume3Month:          q.AverageDailyVolume3Month,
+			Bid:                               q.Bid,
+			BidSize:                           q.BidSize,
+			FiftyDayAverage:                   q.FiftyDayAverage,
+			FiftyDayAverageChange:             q.FiftyDayAverageChange,
+			FiftyDayAverageChangePercent:      q.FiftyDayAverageChangePercent,
+			FiftyTwoWeekChangePercent:         q.FiftyTwoWeekChangePercent,
+			LongName:                          q.LongName,
+			MessageBoardId:                    q.MessageBoardId,
+			RegularMarketDayHigh:              q.RegularMarketDayHigh,
+			RegularMarketDayLow:               q.RegularMarketDayLow,
+			RegularMarketDayRange:             q.RegularMarketDayRange,
+			RegularMarketOpen:                 q.RegularMarketOpen,
+			RegularMarketVolume:               q.RegularMarketVolume,
+			TwoHundredDayAverage:              q.TwoHundredDayAverage,
+			TwoHundredDayAverageChange:        q.TwoHundredDayAverageChange,
+			TwoHundredDayAverageChangePercent: q.TwoHundredDayAverageChangePercent,
+		}
+	case QuoteTypeCryptoPair:
+		return &CryptoQuote{
+			BaseQuote:                         base,
+			AverageDailyVolume10Day:           q.AverageDailyVolume10Day,
+			AverageDailyVolume3Month:          q.AverageDailyVolume3Month,
+			CirculatingSupply:                 q.CirculatingSupply,
+			CoinImageUrl:                      q.CoinImageUrl,
+			CoinMarketCapLink:                 q.CoinMarketCapLink,
+			CryptoTradeable:                   q.CryptoTradeable,
+			FiftyDayAverage:                   q.FiftyDayAverage,
+			FiftyDayAverageChange:             q.FiftyDayAverageChange,
+			FiftyDayAverageChangePercent:      q.FiftyDayAverageChangePercent,
+			FiftyTwoWeekChangePercent:         q.FiftyTwoWeekChangePercent,
+			FromCurrency:                      q.FromCurrency,
+			LastMarket:                        q.LastMarket,
+			LogoUrl:                           q.LogoUrl,
+			LongName:                          q.LongName,
+			MarketCap:                         q.MarketCap,
+			MessageBoardId:                    q.MessageBoardId,
+			RegularMarketDayHigh:              q.RegularMarketDayHigh,
+			RegularMarketDayLow:               q.RegularMarketDayLow,
+			RegularMarketDayRange:             q.RegularMarketDayRange,
+			RegularMarketOpen:                 q.RegularMarketOpen,
+			RegularMarketVolume:               q.RegularMarketVolume,
+			StartDate:                         q.StartDate,
+			ToCurrency:                        q.ToCurrency,
+			TwoHundredDayAverage:              q.TwoHundredDayAverage,
+			TwoHundredDayAverageChange:        q.TwoHundredDayAverageChange,
+			TwoHundredDayAverageChangePercent: q.TwoHundredDayAverageChangePercent,
+			Volume24Hr:                        q.Volume24Hr,
+			VolumeAllCurrencies:               q.VolumeAllCurrencies,
+		}
+	case QuoteTypeFuture:
+		return &FutureQuote{
+			BaseQuote:                         base,
+			Ask:                               q.Ask,
+			AverageDailyVolume10Day:           q.AverageDailyVolume10Day,
+			AverageDailyVolume3Month:          q.AverageDailyVolume3Month,
+			Bid:                               q.Bid,
+			ContractSymbol:                    q.ContractSymbol,
+			FiftyDayAverage:                   q.FiftyDayAverage,
+			FiftyDayAverageChange:             q.FiftyDayAverageChange,
+			FiftyDayAverageChangePercent:      q.FiftyDayAverageChangePercent,
+			FiftyTwoWeekChangePercent:         q.FiftyTwoWeekChangePercent,
+			OpenInterest:                      q.OpenInterest,
+			RegularMarketDayHigh:              q.RegularMarketDayHigh,
+			RegularMarketDayLow:               q.RegularMarketDayLow,
+			RegularMarketDayRange:             q.RegularMarketDayRange,
+			RegularMarketOpen:                 q.RegularMarketOpen,
+			RegularMarketVolume:               q.RegularMarketVolume,
+			TwoHundredDayAverage:              q.TwoHundredDayAverage,
+			TwoHundredDayAverageChange:        q.TwoHundredDayAverageChange,
+			TwoHundredDayAverageChangePercent: q.TwoHundredDayAverageChangePercent,
+			UnderlyingExchangeSymbol:          q.UnderlyingExchangeSymbol,
+			UnderlyingSymbol:                  q.UnderlyingSymbol,
+		}
+	case QuoteTypeOption:
+		return &OptionQuote{
+			BaseQuote:             base,
+			Ask:                   q.Ask,
+			Bid:                   q.Bid,
+			ExpireDate:            q.ExpireDate,
+			ExpireIsoDate:         q.ExpireIsoDate,
+			HeadSymbolAsString:    q.HeadSymbolAsString,
+			OpenInterest:          q.OpenInterest,
+			OptionsType:           q.OptionsType,
+			RegularMarketDayHigh:  q.RegularMarketDayHigh,
+			RegularMarketDayLow:   q.RegularMarketDayLow,
+			RegularMarketDayRange: q.RegularMarketDayRange,
+			RegularMarketOpen:     q.RegularMarketOpen,
+			RegularMarketVolume:   q.RegularMarketVolume,
+			Strike:                q.Strike,
+			UnderlyingShortName:   q.UnderlyingShortName,
+			UnderlyingSymbol:      q.UnderlyingSymbol,
+		}
+	default:
+		return &base
+	}
+}
+
+// base returns the fields of the quote shared by all quote types.
+func (q *Quote) base() BaseQuote {
+	return BaseQuote{
+		Currency:                      q.Currency,
+		CustomPriceAlertConfidence:    q.CustomPriceAlertConfidence,
+		EsgPopulated:                  q.EsgPopulated,
+		Exchange:                      q.Exchange,
+		ExchangeDataDelayedBy:         q.ExchangeDataDelayedBy,
+		ExchangeTimezoneName:          q.ExchangeTimezoneName,
+		ExchangeTimezoneShortName:     q.ExchangeTimezoneShortName,
+		FiftyTwoWeekHigh:              q.FiftyTwoWeekHigh,
+		FiftyTwoWeekHighChange:        q.FiftyTwoWeekHighChange,
+		FiftyTwoWeekHighChangePercent: q.FiftyTwoWeekHighChangePercent,
+		FiftyTwoWeekLow:               q.FiftyTwoWeekLow,
+		FiftyTwoWeekLowChange:         q.FiftyTwoWeekLowChange,
+		FiftyTwoWeekLowChangePercent:  q.FiftyTwoWeekLowChangePercent,
+		FiftyTwoWeekRange:             q.FiftyTwoWeekRange,
+		FirstTradeDateMilliseconds:    q.FirstTradeDateMilliseconds,
+		FullExchangeName:              q.FullExchangeName,
+		GmtOffSetMilliseconds:         q.GmtOffSetMilliseconds,
+		Language:                      q.Language,
+		Market:                        q.Market,
+		MarketState:                   q.MarketState,
+		PostMarketChange:              q.PostMarketChange,
+		PostMarketChangePercent:       q.PostMarketChangePercent,
+		PostMarketPrice:               q.PostMarketPrice,
+		PostMarketTime:                q.PostMarketTime,
+		PreMarketChange:               q.PreMarketChange,
+		PreMarketChangePercent:        q.PreMarketChangePercent,
+		PreMarketPrice:                q.PreMarketPrice,
+		PreMarketTime:                 q.PreMarketTime,
+		PriceHint:                     q.PriceHint,
+		QuoteSourceName:               q.QuoteSourceName,
+		QuoteType:                     q.QuoteType,
+		Region:                        q.Region,
+		RegularMarketChange:           q.RegularMarketChange,
+		RegularMarketChangePercent:    q.RegularMarketChangePercent,
+		RegularMarketPreviousClose:    q.RegularMarketPreviousClose,
+		RegularMarketPrice:            q.RegularMarketPrice,
+		RegularMarketTime:             q.RegularMarketTime,
+		ShortName:                     q.ShortName,
+		SourceInterval:                q.SourceInterval,
+		Symbol:                        q.Symbol,
+		Tradeable:                     q.Tradeable,
+		Triggerable:                   q.Triggerable,
+		TypeDisp:                      q.TypeDisp,
+	}
+}
+
+// QuoteViews returns the views of the given quotes, in the same order.
+func QuoteViews(quotes []Quote) []QuoteView {
+	views := make([]QuoteView, len(quotes))
+	for i := range quotes {
+		views[i] = quotes[i].View()
+	}
+	return views
+}
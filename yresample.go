@@ -0,0 +1,164 @@
+package dorfyn
+
+import (
+	"sort"
+	"time"
+)
+
+// CalendarPeriod is a calendar-based resampling period.
+type CalendarPeriod int
+
+const (
+	// PeriodNone means the resampling is based on a duration instead of a calendar period.
+	PeriodNone CalendarPeriod = iota
+	// PeriodDay groups bars by calendar day.
+	PeriodDay
+	// PeriodWeek groups bars by week, starting on Monday.
+	PeriodWeek
+	// PeriodMonth groups bars by calendar month.
+	PeriodMonth
+	// PeriodQuarter groups bars by calendar quarter.
+	PeriodQuarter
+	// PeriodYear groups bars by calendar year.
+	PeriodYear
+)
+
+// ResampleParams holds the options of a Resample call. Exactly one of Duration and Period must be set.
+type ResampleParams struct {
+	// Duration is the length of the resampled bars. Durations of a day or more are anchored at midnight of January 1st
+	// 1970 in Location: with a two day duration, bars start on even days since then.
+	Duration time.Duration
+	// Period is the calendar period of the resampled bars.
+	Period CalendarPeriod
+	// Location is the time zone used to find the boundaries of days and calendar periods, usually the exchange's one.
+	// Defaults to UTC.
+	Location *time.Location
+	// SessionOpen is the time of day the regular session opens at, as an offset from midnight in Location. Durations
+	// shorter than a day are anchored at it, and never span two days: with a 9:30 open, four hour bars cover 9:30 to
+	// 13:30, then 13:30 to 17:30. Defaults to midnight.
+	SessionOpen time.Duration
+}
+
+// Resample aggregates the given bars into bars of the duration or calendar period given by params. Each resampled bar
+// opens at the open of its first bar and closes at the close of its last one, its high and low are the extremes of
+// its bars and its volume is their sum. Its timestamp is the start of its period. Null bars, and bars whose prices are
+// all zero, only contribute their volume; a period holding only such bars gives a null bar.
+func Resample(bars []ChartBar, params ResampleParams) ([]ChartBar, error) {
+	if (params.Duration > 0) == (params.Period != PeriodNone) {
+		return nil, CreateArgumentError("Exactly one of Duration and Period must be provided to Resample")
+	}
+	if params.Duration < 0 {
+		return nil, CreateArgumentError("Negative duration provided to Resample")
+	}
+	if params.Location == nil {
+		params.Location = time.UTC
+	}
+
+	sorted := make([]ChartBar, len(bars))
+	copy(sorted, bars)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	var resampled []ChartBar
+	var current *ChartBar
+	for _, bar := range sorted {
+		start := NewUnixTime(params.bucketStart(bar.Timestamp.In(params.Location)))
+
+		if current == nil || current.Timestamp != start {
+			resampled = append(resampled, ChartBar{Timestamp: start, Null: true})
+			current = &resampled[len(resampled)-1]
+		}
+		current.merge(bar)
+	}
+
+	return resampled, nil
+}
+
+// Resample aggregates the bars of the chart as the Resample function does. Location and SessionOpen default to the
+// exchange's time zone and regular session open, as found in the chart's metadata.
+func (c *Chart) Resample(params ResampleParams) ([]ChartBar, error) {
+	if params.Location == nil {
+		params.Location = c.Meta.Location()
+		if params.SessionOpen == 0 && c.Meta.CurrentTradingPeriod.Regular.Start != 0 {
+			open := c.Meta.CurrentTradingPeriod.Regular.Start.In(params.Location)
+			midnight := time.Date(open.Year(), open.Month(), open.Day(), 0, 0, 0, 0, params.Location)
+			params.SessionOpen = open.Sub(midnight)
+		}
+	}
+	return Resample(c.Bars, params)
+}
+
+// bucketStart returns the start of the resampled bar t belongs to.
+func (params *ResampleParams) bucketStart(t time.Time) time.Time {
+	loc := params.Location
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, loc)
+
+	switch params.Period {
+	case PeriodDay:
+		return midnight
+	case PeriodWeek:
+		return midnight.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	case PeriodMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	case PeriodQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc)
+	case PeriodYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	}
+
+	if params.Duration >= 24*time.Hour {
+		// Multi-day durations are anchored at midnight of January 1st 1970 in the location. Buckets are counted in wall
+		// clock time, read as UTC, so that daylight saving time changes don't shift them.
+		wall := time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+		elapsed := wall.Sub(time.Unix(0, 0))
+		n := elapsed / params.Duration
+		if elapsed < 0 && elapsed%params.Duration != 0 {
+			n--
+		}
+		start := time.Unix(0, 0).UTC().Add(n * params.Duration)
+		return time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), start.Minute(), start.Second(),
+			start.Nanosecond(), loc)
+	}
+
+	anchor := midnight.Add(params.SessionOpen)
+	elapsed := t.Sub(anchor)
+	n := elapsed / params.Duration
+	if elapsed < 0 && elapsed%params.Duration != 0 {
+		// Bars before the open, e.g. pre-market ones, belong to earlier buckets.
+		n--
+	}
+	start := anchor.Add(n * params.Duration)
+	if start.Before(midnight) {
+		return midnight
+	}
+	return start
+}
+
+// merge aggregates bar into the resampled bar.
+func (bar *ChartBar) merge(other ChartBar) {
+	bar.Volume += other.Volume
+	if other.isPlaceholder() {
+		return
+	}
+
+	if bar.isPlaceholder() {
+		bar.Open = other.Open
+		bar.High = other.High
+		bar.Low = other.Low
+		bar.Null = false
+	} else {
+		if other.High.GreaterThan(bar.High) {
+			bar.High = other.High
+		}
+		if other.Low.LessThan(bar.Low) {
+			bar.Low = other.Low
+		}
+	}
+	bar.Close = other.Close
+	bar.AdjClose = other.AdjClose
+}
+
+// isPlaceholder tells whether the bar holds no prices: either a null bar, or a bar whose prices are all zero.
+func (bar *ChartBar) isPlaceholder() bool {
+	return bar.Null || bar.Open.IsZero() && bar.High.IsZero() && bar.Low.IsZero() && bar.Close.IsZero()
+}
@@ -0,0 +1,113 @@
+package dorfyn
+
+import "strconv"
+
+const (
+	// yFinSearchAPI is the path to the Yahoo! finance search API.
+	yFinSearchAPI string = "/v1/finance/search"
+)
+
+// SearchParams holds the options of a Search call. The zero value uses Yahoo! finance's defaults.
+type SearchParams struct {
+	// QuotesCount is the maximum number of matching securities to return. Zero means Yahoo! finance's default.
+	QuotesCount int
+	// NewsCount is the maximum number of news hits to return. Zero means Yahoo! finance's default.
+	NewsCount int
+	// EnableFuzzyQuery allows matches on misspelled queries (e.g. "aple" for Apple).
+	EnableFuzzyQuery bool
+	// Region is the region to search in, e.g. "US". Empty means Yahoo! finance's default.
+	Region string
+	// Language is the language of the results, e.g. "en-US". Empty means Yahoo! finance's default.
+	Language string
+}
+
+// SearchResult is the result of a Search call.
+type SearchResult struct {
+	// Quotes are the securities matching the query, most relevant first.
+	Quotes []SearchQuote `json:"quotes"`
+	// News are the news stories matching the query.
+	News []SearchNews `json:"news"`
+}
+
+// SearchQuote is a security matching a search query.
+type SearchQuote struct {
+	// Symbol is the ticker symbol of the security.
+	Symbol string `json:"symbol"`
+	// ShortName is a short, user-friendly name for the security.
+	ShortName string `json:"shortname"`
+	// LongName is the official name of the security. It's empty for some quote types.
+	LongName string `json:"longname"`
+	// Exchange is the code of the securities exchange on which the security is traded.
+	Exchange string `json:"exchange"`
+	// ExchangeDisplay is the user-friendly name of the exchange.
+	ExchangeDisplay string `json:"exchDisp"`
+	// QuoteType is the type of the security.
+	QuoteType QuoteType `json:"quoteType"`
+	// TypeDisp is a user-friendly representation of the QuoteType.
+	TypeDisp string `json:"typeDisp"`
+	// Sector is the sector of the company. Only set for equities.
+	Sector string `json:"sector,omitempty"`
+	// Industry is the industry of the company. Only set for equities.
+	Industry string `json:"industry,omitempty"`
+	// Score is the relevance of the match; higher is more relevant.
+	Score float64 `json:"score"`
+	// IsYahooFinance tells whether Yahoo! finance has a quote page for the security.
+	IsYahooFinance bool `json:"isYahooFinance"`
+}
+
+// SearchNews is a news story matching a search query.
+type SearchNews struct {
+	// UUID is the unique identifier of the story.
+	UUID string `json:"uuid"`
+	// Title is the headline of the story.
+	Title string `json:"title"`
+	// Publisher is the name of the story's publisher.
+	Publisher string `json:"publisher"`
+	// Link is the URL of the story.
+	Link string `json:"link"`
+	// ProviderPublishTime is the time at which the story was published.
+	ProviderPublishTime UnixTime `json:"providerPublishTime"`
+	// Type is the type of the story, e.g. STORY or VIDEO.
+	Type string `json:"type"`
+	// RelatedTickers are the symbols of the securities the story is about.
+	RelatedTickers []string `json:"relatedTickers,omitempty"`
+}
+
+// Search returns the securities and news matching the given query, typically a company name or part of a symbol.
+// params may be nil to use the default options.
+func Search(query string, params *SearchParams) (*SearchResult, error) {
+	if query == "" {
+		return nil, CreateArgumentError("No query provided to Search")
+	}
+	if params == nil {
+		params = &SearchParams{}
+	}
+	if params.QuotesCount < 0 || params.NewsCount < 0 {
+		return nil, CreateArgumentError("Negative count provided to Search")
+	}
+
+	qp := queryParams{
+		"q":                query,
+		"enableFuzzyQuery": strconv.FormatBool(params.EnableFuzzyQuery),
+	}
+	if params.QuotesCount > 0 {
+		qp["quotesCount"] = strconv.Itoa(params.QuotesCount)
+	}
+	if params.NewsCount > 0 {
+		qp["newsCount"] = strconv.Itoa(params.NewsCount)
+	}
+	if params.Region != "" {
+		qp["region"] = params.Region
+	}
+	if params.Language != "" {
+		qp["lang"] = params.Language
+	}
+
+	resp := SearchResult{}
+	err := client.call(yFinSearchAPI, qp, &resp)
+	if err != nil {
+		return nil, createRemoteError(err)
+	}
+
+	return &resp, nil
+}
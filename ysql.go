@@ -0,0 +1,331 @@
+package dorfyn
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLDialect is the SQL dialect of a database, which determines the syntax of upserts and placeholders.
+type SQLDialect int
+
+const (
+	// SQLDialectSQLite is the dialect of SQLite.
+	SQLDialectSQLite SQLDialect = iota
+	// SQLDialectPostgres is the dialect of PostgreSQL.
+	SQLDialectPostgres
+	// SQLDialectMySQL is the dialect of MySQL and MariaDB.
+	SQLDialectMySQL
+)
+
+// SQLExportParams are the parameters of an SQL exporter.
+type SQLExportParams struct {
+	// Dialect is the dialect of the database. Defaults to SQLDialectSQLite.
+	Dialect SQLDialect
+	// TablePrefix is prepended to the names of the tables.
+	TablePrefix string
+}
+
+// sqlTable is a table of the export schema.
+type sqlTable struct {
+	name    string
+	columns [][2]string
+	keys    []string
+}
+
+var (
+	// sqlQuotesTable holds quote snapshots, keyed by symbol and regular market time.
+	sqlQuotesTable = sqlTable{
+		name: "quotes",
+		columns: [][2]string{
+			{"symbol", "VARCHAR(64) NOT NULL"},
+			{"time", "BIGINT NOT NULL"},
+			{"quote_type", "VARCHAR(32)"},
+			{"short_name", "VARCHAR(255)"},
+			{"currency", "VARCHAR(16)"},
+			{"exchange", "VARCHAR(32)"},
+			{"market_state", "VARCHAR(16)"},
+			{"price", "DOUBLE PRECISION"},
+			{"change", "DOUBLE PRECISION"},
+			{"change_percent", "DOUBLE PRECISION"},
+			{"open", "DOUBLE PRECISION"},
+			{"day_high", "DOUBLE PRECISION"},
+			{"day_low", "DOUBLE PRECISION"},
+			{"previous_close", "DOUBLE PRECISION"},
+			{"bid", "DOUBLE PRECISION"},
+			{"ask", "DOUBLE PRECISION"},
+			{"volume", "BIGINT"},
+			{"market_cap", "BIGINT"},
+			{"data", "TEXT"},
+		},
+		keys: []string{"symbol", "time"},
+	}
+
+	// sqlBarsTable holds chart bars, keyed by symbol, interval and timestamp.
+	sqlBarsTable = sqlTable{
+		name: "bars",
+		columns: [][2]string{
+			{"symbol", "VARCHAR(64) NOT NULL"},
+			{"bar_interval", "VARCHAR(8) NOT NULL"},
+			{"time", "BIGINT NOT NULL"},
+			{"open", "NUMERIC(24,8)"},
+			{"high", "NUMERIC(24,8)"},
+			{"low", "NUMERIC(24,8)"},
+			{"close", "NUMERIC(24,8)"},
+			{"adj_close", "NUMERIC(24,8)"},
+			{"volume", "BIGINT"},
+		},
+		keys: []string{"symbol", "bar_interval", "time"},
+	}
+
+	// sqlOptionsTable holds option contract snapshots, keyed by contract symbol and snapshot time.
+	sqlOptionsTable = sqlTable{
+		name: "options",
+		columns: [][2]string{
+			{"symbol", "VARCHAR(64) NOT NULL"},
+			{"time", "BIGINT NOT NULL"},
+			{"underlying", "VARCHAR(64)"},
+			{"option_type", "VARCHAR(8)"},
+			{"expiration", "BIGINT"},
+			{"strike", "DOUBLE PRECISION"},
+			{"currency", "VARCHAR(16)"},
+			{"last_price", "DOUBLE PRECISION"},
+			{"change", "DOUBLE PRECISION"},
+			{"percent_change", "DOUBLE PRECISION"},
+			{"bid", "DOUBLE PRECISION"},
+			{"ask", "DOUBLE PRECISION"},
+			{"volume", "BIGINT"},
+			{"open_interest", "BIGINT"},
+			{"implied_volatility", "DOUBLE PRECISION"},
+			{"in_the_money", "BOOLEAN"},
+			{"last_trade_date", "BIGINT"},
+		},
+		keys: []string{"symbol", "time"},
+	}
+)
+
+// SQLExporter writes quotes, chart bars and option chains to a relational database through database/sql. Rows are
+// upserted, so exporting the same data twice leaves a single copy of it. Times are stored as Unix times in seconds.
+//
+// The exporter doesn't depend on any database driver: open the database with the driver of your choice, and pick the
+// matching dialect. See examples/sqlite for a round trip through SQLite.
+type SQLExporter struct {
+	db      *sql.DB
+	dialect SQLDialect
+	prefix  string
+}
+
+// NewSQLExporter returns an exporter writing to the given database.
+func NewSQLExporter(db *sql.DB, params *SQLExportParams) *SQLExporter {
+	e := &SQLExporter{db: db}
+	if params != nil {
+		e.dialect = params.Dialect
+		e.prefix = params.TablePrefix
+	}
+	return e
+}
+
+// CreateSchema creates the tables of the export, unless they already exist.
+func (e *SQLExporter) CreateSchema() error {
+	for _, t := range []sqlTable{sqlQuotesTable, sqlBarsTable, sqlOptionsTable} {
+		if _, err := e.db.Exec(e.createStatement(t)); err != nil {
+			logError("Can't create table %s: %v\n", e.prefix+t.name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportQuotes upserts the given quotes, keyed by symbol and regular market time. Quotes with no regular market time
+// are timed with the current time. The full quote is also stored as JSON in the data column.
+func (e *SQLExporter) ExportQuotes(quotes []Quote) error {
+	now := NewUnixTime(time.Now())
+	return e.upsert(sqlQuotesTable, len(quotes), func(i int) ([]any, error) {
+		q := &quotes[i]
+		if q.Symbol == nil {
+			return nil, CreateArgumentError("Quote with no symbol provided to ExportQuotes")
+		}
+		t := now
+		if q.RegularMarketTime != nil {
+			t = *q.RegularMarketTime
+		}
+		data, err := json.Marshal(q)
+		if err != nil {
+			return nil, err
+		}
+		return []any{
+			*q.Symbol, int64(t), sqlValue(q.QuoteType), sqlValue(q.ShortName), sqlValue(q.Currency),
+			sqlValue(q.Exchange), sqlValue(q.MarketState), sqlValue(q.RegularMarketPrice),
+			sqlValue(q.RegularMarketChange), sqlValue(q.RegularMarketChangePercent), sqlValue(q.RegularMarketOpen),
+			sqlValue(q.RegularMarketDayHigh), sqlValue(q.RegularMarketDayLow), sqlValue(q.RegularMarketPreviousClose),
+			sqlValue(q.Bid), sqlValue(q.Ask), sqlValue(q.RegularMarketVolume), sqlValue(q.MarketCap), string(data),
+		}, nil
+	})
+}
+
+// ExportBars upserts the given bars of the given symbol and interval, keyed by symbol, interval and timestamp. The
+// prices of null bars are written as NULL.
+func (e *SQLExporter) ExportBars(symbol string, interval Interval, bars []ChartBar) error {
+	if symbol == "" {
+		return CreateArgumentError("No symbol provided to ExportBars")
+	}
+	if interval == "" {
+		interval = Interval1Day
+	}
+	return e.upsert(sqlBarsTable, len(bars), func(i int) ([]any, error) {
+		b := &bars[i]
+		if b.Null {
+			return []any{symbol, string(interval), int64(b.Timestamp), nil, nil, nil, nil, nil, b.Volume}, nil
+		}
+		return []any{
+			symbol, string(interval), int64(b.Timestamp), b.Open, b.High, b.Low, b.Close, b.AdjClose, b.Volume,
+		}, nil
+	})
+}
+
+// ExportChart upserts the bars of the given chart. See ExportBars.
+func (e *SQLExporter) ExportChart(chart *Chart) error {
+	if chart == nil {
+		return CreateArgumentError("No chart provided to ExportChart")
+	}
+	return e.ExportBars(chart.Meta.Symbol, Interval(chart.Meta.DataGranularity), chart.Bars)
+}
+
+// ExportOptionChain upserts the contracts of the given chain, keyed by contract symbol and the given snapshot time,
+// which defaults to the current time.
+func (e *SQLExporter) ExportOptionChain(chain *OptionChain, at time.Time) error {
+	if chain == nil {
+		return CreateArgumentError("No option chain provided to ExportOptionChain")
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	type leg struct {
+		optionType OptionType
+		contract   *Contract
+	}
+	var legs []leg
+	for _, s := range chain.Straddles {
+		if s.Call != nil {
+			legs = append(legs, leg{OptionTypeCall, s.Call})
+		}
+		if s.Put != nil {
+			legs = append(legs, leg{OptionTypePut, s.Put})
+		}
+	}
+
+	return e.upsert(sqlOptionsTable, len(legs), func(i int) ([]any, error) {
+		c := legs[i].contract
+		return []any{
+			c.Symbol, at.Unix(), chain.Meta.UnderlyingSymbol, string(legs[i].optionType), int64(c.Expiration),
+			c.Strike, c.Currency, c.LastPrice, c.Change, c.PercentChange, c.Bid, c.Ask, c.Volume, c.OpenInterest,
+			c.ImpliedVolatility, c.InTheMoney, int64(c.LastTradeDate),
+		}, nil
+	})
+}
+
+// upsert upserts n rows in the given table, in a single transaction. row returns the values of each row, in the order
+// of the table's columns.
+func (e *SQLExporter) upsert(t sqlTable, n int, row func(i int) ([]any, error)) error {
+	if n == 0 {
+		return nil
+	}
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(e.upsertStatement(t))
+	if err != nil {
+		logError("Can't prepare upsert into %s: %v\n", e.prefix+t.name, err)
+		return err
+	}
+	defer stmt.Close()
+
+	for i := 0; i < n; i++ {
+		values, err := row(i)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			logError("Can't upsert into %s: %v\n", e.prefix+t.name, err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// createStatement returns the statement creating the given table, unless it already exists.
+func (e *SQLExporter) createStatement(t sqlTable) string {
+	columns := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		columns[i] = e.quote(c[0]) + " " + c[1]
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s, PRIMARY KEY (%s))",
+		e.quote(e.prefix+t.name), strings.Join(columns, ", "), e.quoteAll(t.keys))
+}
+
+// upsertStatement returns the statement upserting a row in the given table.
+func (e *SQLExporter) upsertStatement(t sqlTable) string {
+	names := make([]string, len(t.columns))
+	placeholders := make([]string, len(t.columns))
+	var updates []string
+	for i, c := range t.columns {
+		names[i] = c[0]
+		placeholders[i] = "?"
+		if e.dialect == SQLDialectPostgres {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+		}
+
+		isKey := false
+		for _, k := range t.keys {
+			isKey = isKey || k == c[0]
+		}
+		if isKey {
+			continue
+		}
+		if e.dialect == SQLDialectMySQL {
+			updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", e.quote(c[0]), e.quote(c[0])))
+		} else {
+			updates = append(updates, fmt.Sprintf("%s = excluded.%s", e.quote(c[0]), e.quote(c[0])))
+		}
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		e.quote(e.prefix+t.name), e.quoteAll(names), strings.Join(placeholders, ", "))
+	if e.dialect == SQLDialectMySQL {
+		return stmt + " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	}
+	return stmt + fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", e.quoteAll(t.keys), strings.Join(updates, ", "))
+}
+
+// quote quotes the given identifier, as some column names, such as change or open, are reserved words.
+func (e *SQLExporter) quote(identifier string) string {
+	if e.dialect == SQLDialectMySQL {
+		return "`" + identifier + "`"
+	}
+	return `"` + identifier + `"`
+}
+
+// quoteAll quotes the given identifiers and joins them with commas.
+func (e *SQLExporter) quoteAll(identifiers []string) string {
+	quoted := make([]string, len(identifiers))
+	for i, id := range identifiers {
+		quoted[i] = e.quote(id)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sqlValue returns the value pointed to by p, or nil if p is nil.
+func sqlValue[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
@@ -0,0 +1,110 @@
+package dorfyn
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSQLExporterCreateStatement(t *testing.T) {
+	tests := []struct {
+		dialect SQLDialect
+		want    string
+	}{
+		{
+			SQLDialectSQLite,
+			`CREATE TABLE IF NOT EXISTS "yf_bars" ("symbol" VARCHAR(64) NOT NULL, "bar_interval" VARCHAR(8) NOT NULL, ` +
+				`"time" BIGINT NOT NULL, "open" NUMERIC(24,8), "high" NUMERIC(24,8), "low" NUMERIC(24,8), ` +
+				`"close" NUMERIC(24,8), "adj_close" NUMERIC(24,8), "volume" BIGINT, ` +
+				`PRIMARY KEY ("symbol", "bar_interval", "time"))`,
+		},
+		{
+			SQLDialectPostgres,
+			`CREATE TABLE IF NOT EXISTS "yf_bars" ("symbol" VARCHAR(64) NOT NULL, "bar_interval" VARCHAR(8) NOT NULL, ` +
+				`"time" BIGINT NOT NULL, "open" NUMERIC(24,8), "high" NUMERIC(24,8), "low" NUMERIC(24,8), ` +
+				`"close" NUMERIC(24,8), "adj_close" NUMERIC(24,8), "volume" BIGINT, ` +
+				`PRIMARY KEY ("symbol", "bar_interval", "time"))`,
+		},
+		{
+			SQLDialectMySQL,
+			"CREATE TABLE IF NOT EXISTS `yf_bars` (`symbol` VARCHAR(64) NOT NULL, `bar_interval` VARCHAR(8) NOT NULL, " +
+				"`time` BIGINT NOT NULL, `open` NUMERIC(24,8), `high` NUMERIC(24,8), `low` NUMERIC(24,8), " +
+				"`close` NUMERIC(24,8), `adj_close` NUMERIC(24,8), `volume` BIGINT, " +
+				"PRIMARY KEY (`symbol`, `bar_interval`, `time`))",
+		},
+	}
+
+	for _, tt := range tests {
+		e := NewSQLExporter(nil, &SQLExportParams{Dialect: tt.dialect, TablePrefix: "yf_"})
+		if got := e.createStatement(sqlBarsTable); got != tt.want {
+			t.Errorf("dialect %d:\ngot  %s\nwant %s", tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestSQLExporterUpsertStatement(t *testing.T) {
+	tests := []struct {
+		dialect SQLDialect
+		want    string
+	}{
+		{
+			SQLDialectSQLite,
+			`INSERT INTO "yf_bars" ("symbol", "bar_interval", "time", "open", "high", "low", "close", "adj_close", ` +
+				`"volume") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT ("symbol", "bar_interval", "time") DO UPDATE ` +
+				`SET "open" = excluded."open", "high" = excluded."high", "low" = excluded."low", ` +
+				`"close" = excluded."close", "adj_close" = excluded."adj_close", "volume" = excluded."volume"`,
+		},
+		{
+			SQLDialectPostgres,
+			`INSERT INTO "yf_bars" ("symbol", "bar_interval", "time", "open", "high", "low", "close", "adj_close", ` +
+				`"volume") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT ("symbol", "bar_interval", "time") ` +
+				`DO UPDATE SET "open" = excluded."open", "high" = excluded."high", "low" = excluded."low", ` +
+				`"close" = excluded."close", "adj_close" = excluded."adj_close", "volume" = excluded."volume"`,
+		},
+		{
+			SQLDialectMySQL,
+			"INSERT INTO `yf_bars` (`symbol`, `bar_interval`, `time`, `open`, `high`, `low`, `close`, `adj_close`, " +
+				"`volume`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `open` = VALUES(`open`), " +
+				"`high` = VALUES(`high`), `low` = VALUES(`low`), `close` = VALUES(`close`), " +
+				"`adj_close` = VALUES(`adj_close`), `volume` = VALUES(`volume`)",
+		},
+	}
+
+	for _, tt := range tests {
+		e := NewSQLExporter(nil, &SQLExportParams{Dialect: tt.dialect, TablePrefix: "yf_"})
+		if got := e.upsertStatement(sqlBarsTable); got != tt.want {
+			t.Errorf("dialect %d:\ngot  %s\nwant %s", tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestSQLExporterUpsertStatementColumns(t *testing.T) {
+	for _, dialect := range []SQLDialect{SQLDialectSQLite, SQLDialectPostgres, SQLDialectMySQL} {
+		e := NewSQLExporter(nil, &SQLExportParams{Dialect: dialect})
+		for _, table := range []sqlTable{sqlQuotesTable, sqlBarsTable, sqlOptionsTable} {
+			stmt := e.upsertStatement(table)
+			values := stmt[strings.Index(stmt, "VALUES (")+len("VALUES (") : strings.Index(stmt, ") ON ")]
+			if n := len(strings.Split(values, ", ")); n != len(table.columns) {
+				t.Errorf("dialect %d, table %s: %d placeholders for %d columns", dialect, table.name, n,
+					len(table.columns))
+			}
+
+			updates := stmt[strings.Index(stmt, "UPDATE "):]
+			for _, key := range table.keys {
+				if strings.Contains(updates, e.quote(key)+" = ") {
+					t.Errorf("dialect %d, table %s: key %s is updated", dialect, table.name, key)
+				}
+			}
+		}
+	}
+}
+
+func TestSQLExporterNilArguments(t *testing.T) {
+	e := NewSQLExporter(nil, nil)
+	if err := e.ExportChart(nil); err == nil || !strings.Contains(err.Error(), apiErrorCode) {
+		t.Errorf("ExportChart(nil) = %v, want an argument error", err)
+	}
+	if err := e.ExportOptionChain(nil, time.Time{}); err == nil || !strings.Contains(err.Error(), apiErrorCode) {
+		t.Errorf("ExportOptionChain(nil) = %v, want an argument error", err)
+	}
+}
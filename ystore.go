@@ -0,0 +1,398 @@
+package dorfyn
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// storeHeader is the header line of the store files.
+	storeHeader = []string{"timestamp", "open", "high", "low", "close", "adjclose", "volume"}
+
+	// defaultStoreOverlaps are the default overlap windows by interval: long enough to catch the late corrections of the
+	// most recent bars, short enough not to re-download much.
+	defaultStoreOverlaps = map[Interval]time.Duration{
+		Interval1Min:   24 * time.Hour,
+		Interval2Min:   24 * time.Hour,
+		Interval5Min:   24 * time.Hour,
+		Interval15Min:  24 * time.Hour,
+		Interval30Min:  24 * time.Hour,
+		Interval60Min:  24 * time.Hour,
+		Interval90Min:  24 * time.Hour,
+		Interval1Hour:  24 * time.Hour,
+		Interval1Day:   10 * 24 * time.Hour,
+		Interval5Day:   15 * 24 * time.Hour,
+		Interval1Week:  21 * 24 * time.Hour,
+		Interval1Month: 100 * 24 * time.Hour,
+		Interval3Month: 200 * 24 * time.Hour,
+	}
+)
+
+// HistoryStoreParams are the parameters of a history store.
+type HistoryStoreParams struct {
+	// Overlap is how far before the last stored bar updates start fetching, to pick up late corrections of the most
+	// recent bars. Defaults to a few bars' worth, depending on the interval.
+	Overlap time.Duration
+	// IncludePrePost includes the pre and post market bars of intraday series.
+	IncludePrePost bool
+}
+
+// HistoryStore persists bar series on disk, in one CSV file per symbol and interval, and updates them incrementally.
+// It is safe for concurrent use within a process, but not across processes sharing its directory.
+type HistoryStore struct {
+	dir    string
+	params HistoryStoreParams
+
+	// mu guards locks, which holds a lock for each series in use, so that different series can be updated
+	// concurrently. Locks are dropped once no one uses them, so the map doesn't grow with the number of series.
+	mu    sync.Mutex
+	locks map[string]*seriesLock
+}
+
+// seriesLock is the lock of a series, with the number of callers holding or waiting for it.
+type seriesLock struct {
+	sync.Mutex
+	users int
+}
+
+// OpenHistoryStore opens the history store in the given directory, creating it if needed.
+func OpenHistoryStore(dir string, params *HistoryStoreParams) (*HistoryStore, error) {
+	if dir == "" {
+		return nil, CreateArgumentError("No directory provided to OpenHistoryStore")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		logError("Can't create history store directory: %v\n", err)
+		return nil, err
+	}
+
+	s := &HistoryStore{dir: dir, locks: map[string]*seriesLock{}}
+	if params != nil {
+		s.params = *params
+	}
+	return s, nil
+}
+
+// Load returns the stored bars of the given symbol and interval, in chronological order, or none if nothing is
+// stored.
+func (s *HistoryStore) Load(symbol string, interval Interval) ([]ChartBar, error) {
+	defer s.lock(symbol, interval)()
+	return s.load(symbol, interval)
+}
+
+// LastTimestamp returns the timestamp of the last stored bar of the given symbol and interval, and false if nothing
+// is stored.
+func (s *HistoryStore) LastTimestamp(symbol string, interval Interval) (UnixTime, bool, error) {
+	bars, err := s.Load(symbol, interval)
+	if err != nil || len(bars) == 0 {
+		return 0, false, err
+	}
+	return bars[len(bars)-1].Timestamp, true, nil
+}
+
+// Save merges the given bars into the stored ones of the given symbol and interval. Bars with the timestamp of a
+// stored bar replace it. Null bars are skipped.
+func (s *HistoryStore) Save(symbol string, interval Interval, bars []ChartBar) error {
+	defer s.lock(symbol, interval)()
+
+	stored, err := s.load(symbol, interval)
+	if err != nil {
+		return err
+	}
+	return s.write(symbol, interval, mergeBars(stored, validBars(bars)))
+}
+
+// Delete removes the stored bars of the given symbol and interval.
+func (s *HistoryStore) Delete(symbol string, interval Interval) error {
+	defer s.lock(symbol, interval)()
+
+	err := os.Remove(s.file(symbol, interval))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Update brings the stored bars of the given symbol and interval up to date, and returns them all. With nothing
+// stored, it fetches the bars since start. Otherwise, it only fetches the bars since the last stored one, minus the
+// overlap window, and start is ignored. Null bars are skipped, so they never replace stored prices.
+//
+// Yahoo! finance rewrites past prices when a split occurs, and past adjusted closes when a dividend is paid. When the
+// fetched span holds a split or a dividend and the overlapping bars fetched differ from the stored ones, the whole
+// series is fetched again. Stored bars older than the depth Yahoo! finance serves, see GetHistory, are kept and
+// rescaled for the new splits and dividends with AdjustBars.
+func (s *HistoryStore) Update(symbol string, interval Interval, start time.Time) ([]ChartBar, error) {
+	if symbol == "" {
+		return nil, CreateArgumentError("No symbol provided to HistoryStore.Update")
+	}
+	if interval == "" {
+		interval = Interval1Day
+	}
+
+	defer s.lock(symbol, interval)()
+
+	stored, err := s.load(symbol, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	from := start
+	if len(stored) > 0 {
+		overlap := s.params.Overlap
+		if overlap <= 0 {
+			overlap = defaultStoreOverlaps[interval]
+		}
+		from = stored[len(stored)-1].Timestamp.Time().Add(-overlap)
+	} else if start.IsZero() {
+		return nil, CreateArgumentError("No start time provided to HistoryStore.Update")
+	}
+
+	chart, err := s.fetch(symbol, interval, from)
+	if err != nil {
+		return nil, err
+	}
+	fetched := validBars(chart.Bars)
+
+	if len(stored) > 0 && (len(chart.Splits) > 0 || len(chart.Dividends) > 0) && rewritten(stored, fetched) {
+		logInfo("History of %s was adjusted, fetching it again\n", symbol)
+		last := stored[len(stored)-1].Timestamp
+		if chart, err = s.fetch(symbol, interval, stored[0].Timestamp.Time()); err != nil {
+			return nil, err
+		}
+		fetched = validBars(chart.Bars)
+
+		var older []ChartBar
+		for _, b := range stored {
+			if len(fetched) == 0 || b.Timestamp < fetched[0].Timestamp {
+				older = append(older, b)
+			}
+		}
+		stored = rescaleBars(older, fetched, last, chart.Splits, chart.Dividends)
+	}
+
+	bars := mergeBars(stored, fetched)
+	if err := s.write(symbol, interval, bars); err != nil {
+		return nil, err
+	}
+	return bars, nil
+}
+
+// fetch fetches the bars of the given symbol and interval since the given time.
+func (s *HistoryStore) fetch(symbol string, interval Interval, from time.Time) (*Chart, error) {
+	return GetHistory(symbol, HistoryParams{Interval: interval, Start: from, IncludePrePost: s.params.IncludePrePost})
+}
+
+// validBars returns the given bars, without the null ones.
+func validBars(bars []ChartBar) []ChartBar {
+	valid := make([]ChartBar, 0, len(bars))
+	for _, b := range bars {
+		if !b.Null {
+			valid = append(valid, b)
+		}
+	}
+	return valid
+}
+
+// rescaleBars returns the given old bars, which precede the fetched ones, adjusted for the splits and dividends that
+// occurred after the given time. Their prices are split-adjusted, like the prices of Yahoo! finance charts, and their
+// adjusted closes are also dividend-adjusted. The reference closes of the dividends are found among the fetched bars.
+func rescaleBars(old []ChartBar, fetched []ChartBar, since UnixTime, splits []Split, dividends []Dividend) []ChartBar {
+	if len(old) == 0 {
+		return nil
+	}
+
+	var newSplits []Split
+	for _, split := range splits {
+		if split.Date.Unix() > int64(since) {
+			newSplits = append(newSplits, split)
+		}
+	}
+	var newDividends []Dividend
+	for _, dividend := range dividends {
+		if dividend.Date.Unix() > int64(since) {
+			newDividends = append(newDividends, dividend)
+		}
+	}
+
+	adjusted := AdjustBars(old, newSplits, nil, AdjustSplits)
+	// The old bars come first in the series, so they keep their indexes.
+	series := append(append([]ChartBar(nil), old...), fetched...)
+	full := AdjustBars(series, newSplits, newDividends, AdjustAll)
+	for i := range adjusted {
+		if old[i].Close.IsPositive() {
+			adjusted[i].AdjClose = old[i].AdjClose.Mul(full[i].Close).Div(old[i].Close)
+		}
+	}
+	return adjusted
+}
+
+// rewritten returns whether any of the fetched bars has different prices than the stored bar with the same
+// timestamp. The last stored bar is ignored, as it may have been stored before its session ended.
+func rewritten(stored []ChartBar, fetched []ChartBar) bool {
+	byTime := make(map[UnixTime]ChartBar, len(fetched))
+	for _, b := range fetched {
+		byTime[b.Timestamp] = b
+	}
+	for _, old := range stored[:len(stored)-1] {
+		b, ok := byTime[old.Timestamp]
+		if ok && (!b.Close.Equal(old.Close) || !b.AdjClose.Equal(old.AdjClose)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeBars merges the given bar series, in chronological order. Bars of the second series replace the bars of the
+// first one with the same timestamp.
+func mergeBars(old []ChartBar, new []ChartBar) []ChartBar {
+	byTime := make(map[UnixTime]ChartBar, len(old)+len(new))
+	for _, b := range old {
+		byTime[b.Timestamp] = b
+	}
+	for _, b := range new {
+		byTime[b.Timestamp] = b
+	}
+
+	bars := make([]ChartBar, 0, len(byTime))
+	for _, b := range byTime {
+		bars = append(bars, b)
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Timestamp < bars[j].Timestamp })
+	return bars
+}
+
+// lock locks the series of the given symbol and interval, and returns the function unlocking it.
+func (s *HistoryStore) lock(symbol string, interval Interval) func() {
+	path := s.file(symbol, interval)
+	s.mu.Lock()
+	l, ok := s.locks[path]
+	if !ok {
+		l = &seriesLock{}
+		s.locks[path] = l
+	}
+	l.users++
+	s.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		s.mu.Lock()
+		if l.users--; l.users == 0 {
+			delete(s.locks, path)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// file returns the path of the file storing the bars of the given symbol and interval.
+func (s *HistoryStore) file(symbol string, interval Interval) string {
+	return filepath.Join(s.dir, url.PathEscape(strings.ToUpper(symbol))+"_"+string(interval)+".csv")
+}
+
+// load reads the bars of the given symbol and interval. Must be called with the series locked.
+func (s *HistoryStore) load(symbol string, interval Interval) ([]ChartBar, error) {
+	f, err := os.Open(s.file(symbol, interval))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(bufio.NewReader(f))
+	r.FieldsPerRecord = len(storeHeader)
+	if _, err := r.Read(); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid history store file %s: %w", f.Name(), err)
+	}
+
+	var bars []ChartBar
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return bars, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid history store file %s: %w", f.Name(), err)
+		}
+
+		bar, err := parseStoredBar(record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid history store file %s: %w", f.Name(), err)
+		}
+		bars = append(bars, bar)
+	}
+}
+
+// parseStoredBar parses a record of a store file.
+func parseStoredBar(record []string) (ChartBar, error) {
+	var bar ChartBar
+	timestamp, err := strconv.ParseInt(record[0], 10, 64)
+	if err != nil {
+		return bar, err
+	}
+	bar.Timestamp = UnixTime(timestamp)
+
+	for i, price := range []*decimal.Decimal{&bar.Open, &bar.High, &bar.Low, &bar.Close, &bar.AdjClose} {
+		if *price, err = decimal.NewFromString(record[i+1]); err != nil {
+			return bar, err
+		}
+	}
+
+	bar.Volume, err = strconv.Atoi(record[6])
+	return bar, err
+}
+
+// write replaces the bars of the given symbol and interval. The file is written next to the existing one and renamed
+// over it, so that an interrupted write never corrupts the store. Must be called with the series locked.
+func (s *HistoryStore) write(symbol string, interval Interval, bars []ChartBar) error {
+	path := s.file(symbol, interval)
+	f, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	cw := csv.NewWriter(w)
+	cw.Write(storeHeader)
+	for _, b := range bars {
+		cw.Write([]string{
+			strconv.FormatInt(int64(b.Timestamp), 10),
+			b.Open.String(),
+			b.High.String(),
+			b.Low.String(),
+			b.Close.String(),
+			b.AdjClose.String(),
+			strconv.Itoa(b.Volume),
+		})
+	}
+	cw.Flush()
+	if err = cw.Error(); err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		logError("Can't write history store file %s: %v\n", path, err)
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
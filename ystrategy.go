@@ -0,0 +1,439 @@
+package dorfyn
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ChainContract is a contract of an option chain, along with the information needed to query it.
+type ChainContract struct {
+	*Contract
+	// Type is OptionTypeCall or OptionTypePut.
+	Type OptionType
+	// Moneyness is the ratio of the strike to the underlying price, or zero if the underlying price is unknown.
+	Moneyness float64
+	// delta caches the delta of the contract, once computed.
+	delta *float64
+}
+
+// Spread returns the difference between the ask and the bid of the contract.
+func (c ChainContract) Spread() float64 {
+	return c.Ask - c.Bid
+}
+
+// RelativeSpread returns the spread of the contract relative to its mid price, or +Inf if it has no mid price.
+func (c ChainContract) RelativeSpread() float64 {
+	mid := (c.Ask + c.Bid) / 2
+	if mid <= 0 {
+		return math.Inf(1)
+	}
+	return c.Spread() / mid
+}
+
+// ContractQuery is a set of contracts of an option chain, narrowed down by chaining filters. Queries are immutable:
+// each filter returns a new query.
+type ContractQuery struct {
+	contracts  []ChainContract
+	underlying float64
+}
+
+// Query returns a query over all the contracts of the chain.
+func (chain *OptionChain) Query() *ContractQuery {
+	q := &ContractQuery{}
+	if underlying, err := UnderlyingPrice(&chain.Meta); err == nil {
+		q.underlying = underlying
+	}
+
+	for i := range chain.Straddles {
+		s := &chain.Straddles[i]
+		for _, leg := range []struct {
+			optionType OptionType
+			contract   *Contract
+		}{{OptionTypeCall, s.Call}, {OptionTypePut, s.Put}} {
+			if leg.contract == nil {
+				continue
+			}
+			c := ChainContract{Contract: leg.contract, Type: leg.optionType}
+			if q.underlying > 0 {
+				c.Moneyness = leg.contract.Strike / q.underlying
+			}
+			q.contracts = append(q.contracts, c)
+		}
+	}
+	return q
+}
+
+// Contracts returns the contracts matching the query.
+func (q *ContractQuery) Contracts() []ChainContract {
+	return append([]ChainContract(nil), q.contracts...)
+}
+
+// Len returns the number of contracts matching the query.
+func (q *ContractQuery) Len() int {
+	return len(q.contracts)
+}
+
+// Where keeps the contracts for which keep returns true.
+func (q *ContractQuery) Where(keep func(c ChainContract) bool) *ContractQuery {
+	result := &ContractQuery{underlying: q.underlying}
+	for _, c := range q.contracts {
+		if keep(c) {
+			result.contracts = append(result.contracts, c)
+		}
+	}
+	return result
+}
+
+// Calls keeps the calls.
+func (q *ContractQuery) Calls() *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.Type == OptionTypeCall })
+}
+
+// Puts keeps the puts.
+func (q *ContractQuery) Puts() *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.Type == OptionTypePut })
+}
+
+// InTheMoney keeps the contracts whose in-the-money status is the given one.
+func (q *ContractQuery) InTheMoney(itm bool) *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.InTheMoney == itm })
+}
+
+// Moneyness keeps the contracts whose strike to underlying price ratio is between min and max, inclusive.
+func (q *ContractQuery) Moneyness(min float64, max float64) *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.Moneyness > 0 && c.Moneyness >= min && c.Moneyness <= max })
+}
+
+// Strikes keeps the contracts whose strike is between min and max, inclusive.
+func (q *ContractQuery) Strikes(min float64, max float64) *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.Strike >= min && c.Strike <= max })
+}
+
+// MinOpenInterest keeps the contracts with at least the given open interest.
+func (q *ContractQuery) MinOpenInterest(n int) *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.OpenInterest >= n })
+}
+
+// MinVolume keeps the contracts with at least the given volume.
+func (q *ContractQuery) MinVolume(n int) *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.Volume >= n })
+}
+
+// MaxSpread keeps the contracts with a bid, an ask, and a spread of at most the given amount.
+func (q *ContractQuery) MaxSpread(spread float64) *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.Bid > 0 && c.Ask >= c.Bid && c.Spread() <= spread })
+}
+
+// MaxRelativeSpread keeps the contracts with a bid, an ask, and a spread of at most the given fraction of their mid
+// price.
+func (q *ContractQuery) MaxRelativeSpread(fraction float64) *ContractQuery {
+	return q.Where(func(c ChainContract) bool { return c.Bid > 0 && c.Ask >= c.Bid && c.RelativeSpread() <= fraction })
+}
+
+// Delta keeps the contracts whose delta, computed from their market price with the given pricing parameters, is
+// between min and max, inclusive. Puts have negative deltas. Contracts whose delta can't be computed are dropped.
+func (q *ContractQuery) Delta(min float64, max float64, params PricingParams) *ContractQuery {
+	result := &ContractQuery{underlying: q.underlying}
+	for _, c := range q.contracts {
+		if c.delta == nil {
+			analysis, err := params.Analyze(c.Contract, c.Type, q.underlying)
+			if err != nil {
+				continue
+			}
+			delta := analysis.Greeks.Delta
+			c.delta = &delta
+		}
+		if *c.delta >= min && *c.delta <= max {
+			result.contracts = append(result.contracts, c)
+		}
+	}
+	return result
+}
+
+// SortBy sorts the contracts with the given less function.
+func (q *ContractQuery) SortBy(less func(a ChainContract, b ChainContract) bool) *ContractQuery {
+	result := &ContractQuery{contracts: q.Contracts(), underlying: q.underlying}
+	sort.SliceStable(result.contracts, func(i, j int) bool { return less(result.contracts[i], result.contracts[j]) })
+	return result
+}
+
+// SortByStrike sorts the contracts by increasing strike.
+func (q *ContractQuery) SortByStrike() *ContractQuery {
+	return q.SortBy(func(a ChainContract, b ChainContract) bool { return a.Strike < b.Strike })
+}
+
+// Nearest returns the contract of the given type whose strike is the nearest to the given one, and false if the query
+// has no contract of that type.
+func (q *ContractQuery) Nearest(optionType OptionType, strike float64) (ChainContract, bool) {
+	var nearest ChainContract
+	found := false
+	for _, c := range q.contracts {
+		if c.Type != optionType {
+			continue
+		}
+		if !found || math.Abs(c.Strike-strike) < math.Abs(nearest.Strike-strike) {
+			nearest, found = c, true
+		}
+	}
+	return nearest, found
+}
+
+// AtTheMoney returns the contract of the given type whose strike is the nearest to the underlying price.
+func (q *ContractQuery) AtTheMoney(optionType OptionType) (ChainContract, bool) {
+	if q.underlying <= 0 {
+		return ChainContract{}, false
+	}
+	return q.Nearest(optionType, q.underlying)
+}
+
+// PriceMode selects the premium used for the legs of a strategy.
+type PriceMode int
+
+const (
+	// PriceMid uses the mid price of each leg.
+	PriceMid PriceMode = iota
+	// PriceNatural pays the ask of long legs and receives the bid of short legs.
+	PriceNatural
+	// PriceLast uses the last price of each leg.
+	PriceLast
+)
+
+// Leg is a position in an option contract, part of a strategy.
+type Leg struct {
+	Contract *Contract
+	Type     OptionType
+	// Quantity is the number of contracts, positive for long positions and negative for short ones.
+	Quantity int
+	// Premium is the price per share paid, or received for short legs, for each contract.
+	Premium float64
+}
+
+// Strategy is a combination of option positions with the same expiration date, with its profit and loss at
+// expiration. Amounts are per share; multiply them by the contract size, usually 100, for amounts per contract.
+type Strategy struct {
+	Name string
+	Legs []Leg
+	// Cost is the net premium of the strategy: positive for a debit, negative for a credit.
+	Cost float64
+	// MaxProfit is the maximum profit at expiration, +Inf if unbounded.
+	MaxProfit float64
+	// MaxLoss is the maximum loss at expiration, as a positive amount, zero if the strategy can't lose, +Inf if
+	// unbounded.
+	MaxLoss float64
+	// Breakevens are the underlying prices at expiration at which the strategy neither makes nor loses money, in
+	// increasing order.
+	Breakevens []float64
+}
+
+// LegSpec describes a leg of a custom strategy.
+type LegSpec struct {
+	Type   OptionType
+	Strike float64
+	// Quantity is the number of contracts, positive for long positions and negative for short ones.
+	Quantity int
+}
+
+// StrategyBuilder builds option strategies out of the contracts of a chain.
+type StrategyBuilder struct {
+	Chain *OptionChain
+	// Pricing selects the premium used for the legs. Defaults to PriceMid.
+	Pricing PriceMode
+}
+
+// Vertical returns a vertical spread: long one contract of the given type at longStrike and short one at
+// shortStrike.
+func (b StrategyBuilder) Vertical(optionType OptionType, longStrike float64, shortStrike float64) (*Strategy, error) {
+	if longStrike == shortStrike {
+		return nil, CreateArgumentError("The strikes of a vertical spread must differ")
+	}
+	name := "bull "
+	if longStrike > shortStrike {
+		name = "bear "
+	}
+	return b.Custom(name+strings.ToLower(string(optionType))+" vertical",
+		LegSpec{Type: optionType, Strike: longStrike, Quantity: 1},
+		LegSpec{Type: optionType, Strike: shortStrike, Quantity: -1})
+}
+
+// Straddle returns a straddle: a call and a put at the same strike, both long if long is true, both short otherwise.
+func (b StrategyBuilder) Straddle(strike float64, long bool) (*Strategy, error) {
+	q, name := sideOf(long)
+	return b.Custom(name+" straddle",
+		LegSpec{Type: OptionTypeCall, Strike: strike, Quantity: q},
+		LegSpec{Type: OptionTypePut, Strike: strike, Quantity: q})
+}
+
+// Strangle returns a strangle: a put at putStrike and a call at callStrike, both long if long is true, both short
+// otherwise.
+func (b StrategyBuilder) Strangle(putStrike float64, callStrike float64, long bool) (*Strategy, error) {
+	if putStrike >= callStrike {
+		return nil, CreateArgumentError("The put strike of a strangle must be below its call strike")
+	}
+	q, name := sideOf(long)
+	return b.Custom(name+" strangle",
+		LegSpec{Type: OptionTypePut, Strike: putStrike, Quantity: q},
+		LegSpec{Type: OptionTypeCall, Strike: callStrike, Quantity: q})
+}
+
+// IronCondor returns a short iron condor: a bull put spread between longPut and shortPut and a bear call spread
+// between shortCall and longCall, with strikes in increasing order.
+func (b StrategyBuilder) IronCondor(longPut float64, shortPut float64, shortCall float64, longCall float64) (*Strategy,
+	error) {
+	if !(longPut < shortPut && shortPut <= shortCall && shortCall < longCall) {
+		return nil, CreateArgumentError("The strikes of an iron condor must be in increasing order")
+	}
+	return b.Custom("iron condor",
+		LegSpec{Type: OptionTypePut, Strike: longPut, Quantity: 1},
+		LegSpec{Type: OptionTypePut, Strike: shortPut, Quantity: -1},
+		LegSpec{Type: OptionTypeCall, Strike: shortCall, Quantity: -1},
+		LegSpec{Type: OptionTypeCall, Strike: longCall, Quantity: 1})
+}
+
+// Custom returns a strategy made of the given legs.
+func (b StrategyBuilder) Custom(name string, specs ...LegSpec) (*Strategy, error) {
+	if b.Chain == nil {
+		return nil, CreateArgumentError("No chain provided to the strategy builder")
+	}
+	if len(specs) == 0 {
+		return nil, CreateArgumentError("No legs provided to the strategy builder")
+	}
+
+	legs := make([]Leg, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Quantity == 0 {
+			return nil, CreateArgumentError("Strategy legs must have a non-zero quantity")
+		}
+
+		var contract *Contract
+		if s := b.Chain.Straddle(spec.Strike); s != nil {
+			contract = s.Call
+			if spec.Type == OptionTypePut {
+				contract = s.Put
+			}
+		}
+		if contract == nil {
+			return nil, CreateArgumentError(fmt.Sprintf("No %s at strike %v in the chain", spec.Type, spec.Strike))
+		}
+
+		premium, err := b.premium(contract, spec.Quantity > 0)
+		if err != nil {
+			return nil, err
+		}
+		legs = append(legs, Leg{Contract: contract, Type: spec.Type, Quantity: spec.Quantity, Premium: premium})
+	}
+
+	return NewStrategy(name, legs), nil
+}
+
+// premium returns the premium of the given contract according to the pricing mode.
+func (b StrategyBuilder) premium(c *Contract, long bool) (float64, error) {
+	var premium float64
+	switch b.Pricing {
+	case PriceNatural:
+		premium = c.Bid
+		if long {
+			premium = c.Ask
+		}
+	case PriceLast:
+		premium = c.LastPrice
+	default:
+		if c.Bid > 0 && c.Ask >= c.Bid {
+			premium = (c.Bid + c.Ask) / 2
+		}
+	}
+	if premium <= 0 {
+		return 0, CreateArgumentError("No usable price for contract " + c.Symbol)
+	}
+	return premium, nil
+}
+
+// NewStrategy returns the strategy made of the given legs, with its cost, maximum profit and loss and breakevens
+// computed from the premiums of the legs.
+func NewStrategy(name string, legs []Leg) *Strategy {
+	s := &Strategy{Name: name, Legs: legs}
+	for _, leg := range legs {
+		s.Cost += float64(leg.Quantity) * leg.Premium
+	}
+
+	// The payoff at expiration is piecewise linear, with kinks at the strikes: its extremes are at zero, at one of
+	// the strikes, or at infinity.
+	strikes := []float64{0}
+	for _, leg := range legs {
+		strikes = append(strikes, leg.Contract.Strike)
+	}
+	sort.Float64s(strikes)
+
+	var slope float64
+	for _, leg := range legs {
+		if leg.Type == OptionTypeCall {
+			slope += float64(leg.Quantity)
+		}
+	}
+
+	// A strategy that can't lose, such as an arbitrage, has no loss rather than a negative one.
+	s.MaxProfit, s.MaxLoss = math.Inf(-1), 0
+	for _, price := range strikes {
+		pnl := s.ProfitAt(price)
+		s.MaxProfit = math.Max(s.MaxProfit, pnl)
+		s.MaxLoss = math.Max(s.MaxLoss, -pnl)
+	}
+	if slope > 0 {
+		s.MaxProfit = math.Inf(1)
+	} else if slope < 0 {
+		s.MaxLoss = math.Inf(1)
+	}
+
+	for i := 1; i < len(strikes); i++ {
+		lo, hi := strikes[i-1], strikes[i]
+		pLo, pHi := s.ProfitAt(lo), s.ProfitAt(hi)
+		if pLo == 0 && i == 1 && lo == 0 {
+			s.Breakevens = appendBreakeven(s.Breakevens, lo)
+		}
+		if pHi == 0 {
+			s.Breakevens = appendBreakeven(s.Breakevens, hi)
+		} else if (pLo < 0) != (pHi < 0) && pLo != 0 {
+			s.Breakevens = appendBreakeven(s.Breakevens, lo+(hi-lo)*pLo/(pLo-pHi))
+		}
+	}
+	if last := strikes[len(strikes)-1]; slope != 0 {
+		pnl := s.ProfitAt(last)
+		if (pnl < 0) == (slope > 0) && pnl != 0 {
+			s.Breakevens = appendBreakeven(s.Breakevens, last-pnl/slope)
+		}
+	}
+
+	return s
+}
+
+// ProfitAt returns the profit, or loss if negative, of the strategy at expiration for the given underlying price.
+func (s *Strategy) ProfitAt(underlying float64) float64 {
+	pnl := -s.Cost
+	for _, leg := range s.Legs {
+		var intrinsic float64
+		if leg.Type == OptionTypeCall {
+			intrinsic = math.Max(underlying-leg.Contract.Strike, 0)
+		} else {
+			intrinsic = math.Max(leg.Contract.Strike-underlying, 0)
+		}
+		pnl += float64(leg.Quantity) * intrinsic
+	}
+	return pnl
+}
+
+// appendBreakeven appends the given breakeven to the given ones, unless it's the same as the last one.
+func appendBreakeven(breakevens []float64, b float64) []float64 {
+	if n := len(breakevens); n > 0 && math.Abs(breakevens[n-1]-b) < 1e-9 {
+		return breakevens
+	}
+	return append(breakevens, b)
+}
+
+// sideOf returns the quantity and name of a long or short position.
+func sideOf(long bool) (int, string) {
+	if long {
+		return 1, "long"
+	}
+	return -1, "short"
+}
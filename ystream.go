@@ -0,0 +1,516 @@
+package dorfyn
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamURL is the URL of Yahoo! finance's streaming quotes websocket.
+	streamURL = "wss://streamer.finance.yahoo.com/?version=2"
+	// defaultStreamMinBackoff is the delay before the first reconnection attempt.
+	defaultStreamMinBackoff = time.Second
+	// defaultStreamMaxBackoff is the maximum delay between reconnection attempts.
+	defaultStreamMaxBackoff = time.Minute
+	// defaultStreamBuffer is the default capacity of the ticks channel.
+	defaultStreamBuffer = 256
+)
+
+// MarketHours is the trading session a streamed tick belongs to.
+type MarketHours int
+
+const (
+	// MarketHoursPre pre market session.
+	MarketHoursPre MarketHours = 0
+	// MarketHoursRegular regular market session.
+	MarketHoursRegular MarketHours = 1
+	// MarketHoursPost post market session.
+	MarketHoursPost MarketHours = 2
+	// MarketHoursExtended extended hours session.
+	MarketHoursExtended MarketHours = 3
+)
+
+// Tick is a real-time price update for a security, as streamed by Yahoo! finance. Fields that are not relevant to
+// the security, or not sent in the update, are zero.
+type Tick struct {
+	Symbol        string
+	Price         float64
+	Time          UnixMilliTime
+	Currency      string
+	Exchange      string
+	QuoteType     QuoteType
+	MarketHours   MarketHours
+	Change        float64
+	ChangePercent float64
+	DayVolume     int64
+	DayHigh       float64
+	DayLow        float64
+	Open          float64
+	PreviousClose float64
+	ShortName     string
+	LastSize      int64
+	Bid           float64
+	BidSize       int64
+	Ask           float64
+	AskSize       int64
+	PriceHint     int64
+
+	// Options only.
+	ExpireDate       UnixMilliTime
+	Strike           float64
+	UnderlyingSymbol string
+	OpenInterest     int64
+	OptionType       OptionType
+	MiniOption       int64
+
+	// Cryptocurrencies only.
+	Volume24Hr        int64
+	VolumeAllCurrency int64
+	FromCurrency      string
+	LastMarket        string
+	CirculatingSupply float64
+	MarketCap         float64
+}
+
+// StreamParams are the parameters of a quote stream.
+type StreamParams struct {
+	// MinBackoff is the delay before the first reconnection attempt. Defaults to one second.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between reconnection attempts; the delay doubles after each failed attempt.
+	// Defaults to one minute.
+	MaxBackoff time.Duration
+	// Buffer is the capacity of the ticks channel. Defaults to 256.
+	Buffer int
+}
+
+// Stream is a connection to Yahoo! finance's streaming quotes. It reconnects, and resubscribes to its symbols,
+// whenever the connection drops.
+type Stream struct {
+	params StreamParams
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+	// dialing is the network connection of the websocket being opened, if any, closed to abort its handshake.
+	dialing net.Conn
+	symbols map[string]struct{}
+
+	ticks  chan Tick
+	errors chan error
+	done   chan struct{}
+	// cancel aborts the connection attempt in progress, if any.
+	cancel context.CancelFunc
+	closed sync.Once
+	wg     sync.WaitGroup
+}
+
+// NewStream opens a quote stream. The connection is established in the background; use Subscribe to receive ticks
+// for symbols, and Close to release the stream.
+func NewStream(params *StreamParams) *Stream {
+	s := &Stream{
+		symbols: map[string]struct{}{},
+		errors:  make(chan error, 16),
+		done:    make(chan struct{}),
+	}
+	if params != nil {
+		s.params = *params
+	}
+	if s.params.MinBackoff <= 0 {
+		s.params.MinBackoff = defaultStreamMinBackoff
+	}
+	if s.params.MaxBackoff < s.params.MinBackoff {
+		s.params.MaxBackoff = defaultStreamMaxBackoff
+		if s.params.MaxBackoff < s.params.MinBackoff {
+			s.params.MaxBackoff = s.params.MinBackoff
+		}
+	}
+	if s.params.Buffer <= 0 {
+		s.params.Buffer = defaultStreamBuffer
+	}
+	s.ticks = make(chan Tick, s.params.Buffer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go s.run(ctx)
+	return s
+}
+
+// Ticks returns the channel on which the ticks are delivered. It is closed when the stream is closed.
+func (s *Stream) Ticks() <-chan Tick {
+	return s.ticks
+}
+
+// Errors returns the channel on which connection and decoding errors are reported. Errors are dropped if the channel
+// is not drained. It is closed when the stream is closed.
+func (s *Stream) Errors() <-chan error {
+	return s.errors
+}
+
+// Subscribe adds the given symbols to the stream.
+func (s *Stream) Subscribe(symbols ...string) error {
+	if len(symbols) == 0 {
+		return CreateArgumentError("No symbols provided to Subscribe")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, symbol := range symbols {
+		s.symbols[strings.ToUpper(symbol)] = struct{}{}
+	}
+	return s.send("subscribe", symbols)
+}
+
+// Unsubscribe removes the given symbols from the stream.
+func (s *Stream) Unsubscribe(symbols ...string) error {
+	if len(symbols) == 0 {
+		return CreateArgumentError("No symbols provided to Unsubscribe")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(s.symbols, strings.ToUpper(symbol))
+	}
+	return s.send("unsubscribe", symbols)
+}
+
+// Symbols returns the symbols the stream is subscribed to.
+func (s *Stream) Symbols() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscribed()
+}
+
+// Close closes the stream and its channels.
+func (s *Stream) Close() error {
+	var err error
+	s.closed.Do(func() {
+		close(s.done)
+		s.cancel()
+		s.mu.Lock()
+		if s.conn != nil {
+			err = s.conn.Close()
+		}
+		if s.dialing != nil {
+			s.dialing.Close()
+		}
+		s.mu.Unlock()
+		s.wg.Wait()
+		close(s.ticks)
+		close(s.errors)
+	})
+	return err
+}
+
+// send sends a subscription message for the given symbols, if connected. When not connected, the symbols are sent
+// upon reconnection. Must be called with s.mu held.
+func (s *Stream) send(action string, symbols []string) error {
+	if s.conn == nil || len(symbols) == 0 {
+		return nil
+	}
+	if err := s.conn.WriteJSON(map[string][]string{action: symbols}); err != nil {
+		logError("Stream %s failed: %v\n", action, err)
+		return err
+	}
+	return nil
+}
+
+// subscribed returns the symbols the stream is subscribed to. Must be called with s.mu held.
+func (s *Stream) subscribed() []string {
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// run connects the stream and reads from it until it is closed, reconnecting with exponential backoff. Closing the
+// stream cancels ctx, which aborts any connection attempt in progress.
+func (s *Stream) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	dialer := *websocket.DefaultDialer
+	dialer.NetDialContext = s.dial
+
+	backoff := s.params.MinBackoff
+	for {
+		conn, _, err := dialer.DialContext(ctx, streamURL, http.Header{"User-Agent": {userAgent}})
+		s.mu.Lock()
+		s.dialing = nil
+		s.mu.Unlock()
+		if err == nil {
+			s.mu.Lock()
+			select {
+			case <-s.done:
+				s.mu.Unlock()
+				conn.Close()
+				return
+			default:
+			}
+			s.conn = conn
+			err = s.send("subscribe", s.subscribed())
+			s.mu.Unlock()
+
+			if err == nil {
+				logInfo("Stream connected\n")
+				backoff = s.params.MinBackoff
+				err = s.read(conn)
+			}
+
+			s.mu.Lock()
+			s.conn = nil
+			s.mu.Unlock()
+			conn.Close()
+		}
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		logError("Stream disconnected, reconnecting in %v: %v\n", backoff, err)
+		s.report(err)
+		select {
+		case <-s.done:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > s.params.MaxBackoff {
+			backoff = s.params.MaxBackoff
+		}
+	}
+}
+
+// dial opens the network connection of a websocket, and records it so that Close can abort the handshake.
+func (s *Stream) dial(ctx context.Context, network string, addr string) (net.Conn, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		conn.Close()
+		return nil, net.ErrClosed
+	default:
+	}
+	s.dialing = conn
+	return conn, nil
+}
+
+// read delivers the ticks read from the given connection until it fails.
+func (s *Stream) read(conn *websocket.Conn) error {
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		tick, ok, err := decodeStreamMessage(message)
+		if err != nil {
+			logError("Stream decoding failed: %v\n", err)
+			s.report(err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		select {
+		case s.ticks <- tick:
+		case <-s.done:
+			return nil
+		}
+	}
+}
+
+// report reports an error on the errors channel, unless it is full.
+func (s *Stream) report(err error) {
+	select {
+	case s.errors <- err:
+	default:
+	}
+}
+
+// decodeStreamMessage decodes a message of the stream. Messages are either a JSON envelope around a base64 encoded
+// PricingData protobuf message, or the bare base64 message. Returns false for messages that are not ticks.
+func decodeStreamMessage(message []byte) (Tick, bool, error) {
+	payload := strings.TrimSpace(string(message))
+	if strings.HasPrefix(payload, "{") {
+		var envelope struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+			return Tick{}, false, err
+		}
+		if envelope.Type != "pricing" || envelope.Message == "" {
+			return Tick{}, false, nil
+		}
+		payload = envelope.Message
+	}
+
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		if data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "=")); err != nil {
+			return Tick{}, false, err
+		}
+	}
+
+	tick, err := decodePricingData(data)
+	if err != nil {
+		return Tick{}, false, err
+	}
+	return tick, tick.Symbol != "", nil
+}
+
+// streamQuoteTypes maps the quote types of the PricingData message to the ones of the quote API.
+var streamQuoteTypes = map[uint64]QuoteType{
+	8:  QuoteTypeEquity,
+	9:  QuoteTypeIndex,
+	11: QuoteTypeMutualFund,
+	13: QuoteTypeOption,
+	14: QuoteTypeForexPair,
+	18: QuoteTypeFuture,
+	20: QuoteTypeETF,
+	41: QuoteTypeCryptoPair,
+}
+
+// errTruncatedPricingData is returned when a PricingData message ends in the middle of a field.
+var errTruncatedPricingData = errors.New("truncated PricingData message")
+
+// decodePricingData decodes a PricingData protobuf message.
+func decodePricingData(data []byte) (Tick, error) {
+	var tick Tick
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return tick, errTruncatedPricingData
+		}
+		data = data[n:]
+		field, wireType := key>>3, key&7
+
+		var varint uint64
+		var raw []byte
+		switch wireType {
+		case 0:
+			varint, n = binary.Uvarint(data)
+			if n <= 0 {
+				return tick, errTruncatedPricingData
+			}
+			data = data[n:]
+		case 1:
+			if len(data) < 8 {
+				return tick, errTruncatedPricingData
+			}
+			varint, data = binary.LittleEndian.Uint64(data), data[8:]
+		case 2:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return tick, errTruncatedPricingData
+			}
+			raw, data = data[n:n+int(length)], data[n+int(length):]
+		case 5:
+			if len(data) < 4 {
+				return tick, errTruncatedPricingData
+			}
+			varint, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		default:
+			return tick, fmt.Errorf("unsupported wire type %d in PricingData message", wireType)
+		}
+
+		float := float64(math.Float32frombits(uint32(varint)))
+		sint := int64(varint>>1) ^ -int64(varint&1)
+		switch field {
+		case 1:
+			tick.Symbol = string(raw)
+		case 2:
+			tick.Price = float
+		case 3:
+			tick.Time = UnixMilliTime(sint)
+		case 4:
+			tick.Currency = string(raw)
+		case 5:
+			tick.Exchange = string(raw)
+		case 6:
+			tick.QuoteType = streamQuoteTypes[varint]
+		case 7:
+			tick.MarketHours = MarketHours(varint)
+		case 8:
+			tick.ChangePercent = float
+		case 9:
+			tick.DayVolume = sint
+		case 10:
+			tick.DayHigh = float
+		case 11:
+			tick.DayLow = float
+		case 12:
+			tick.Change = float
+		case 13:
+			tick.ShortName = string(raw)
+		case 14:
+			tick.ExpireDate = UnixMilliTime(sint)
+		case 15:
+			tick.Open = float
+		case 16:
+			tick.PreviousClose = float
+		case 17:
+			tick.Strike = float
+		case 18:
+			tick.UnderlyingSymbol = string(raw)
+		case 19:
+			tick.OpenInterest = sint
+		case 20:
+			if varint == 1 {
+				tick.OptionType = OptionTypePut
+			}
+		case 21:
+			tick.MiniOption = sint
+		case 22:
+			tick.LastSize = sint
+		case 23:
+			tick.Bid = float
+		case 24:
+			tick.BidSize = sint
+		case 25:
+			tick.Ask = float
+		case 26:
+			tick.AskSize = sint
+		case 27:
+			tick.PriceHint = sint
+		case 28:
+			tick.Volume24Hr = sint
+		case 29:
+			tick.VolumeAllCurrency = sint
+		case 30:
+			tick.FromCurrency = string(raw)
+		case 31:
+			tick.LastMarket = string(raw)
+		case 32:
+			tick.CirculatingSupply = math.Float64frombits(varint)
+		case 33:
+			tick.MarketCap = math.Float64frombits(varint)
+		}
+	}
+
+	// Calls are the default value of the option type, which protobuf doesn't encode.
+	if tick.QuoteType == QuoteTypeOption && tick.OptionType == "" {
+		tick.OptionType = OptionTypeCall
+	}
+	return tick, nil
+}
@@ -0,0 +1,382 @@
+package dorfyn
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// quoteSummaryResponse is a yfin quote summary response.
+type quoteSummaryResponse struct {
+	Inner struct {
+		Result []QuoteSummary `json:"result"`
+		Error  *yError        `json:"error"`
+	} `json:"quoteSummary"`
+}
+
+const (
+	// yFinQuoteSummaryAPI is the path to the Yahoo! finance quote summary API. It must be followed by a symbol.
+	yFinQuoteSummaryAPI string = "/v10/finance/quoteSummary/"
+)
+
+// SummaryModule is the name of a module of the quote summary API.
+type SummaryModule string
+
+const (
+	// SummaryModuleAssetProfile requests the company profile, see AssetProfile.
+	SummaryModuleAssetProfile SummaryModule = "assetProfile"
+	// SummaryModuleFinancialData requests the financial data, see FinancialData.
+	SummaryModuleFinancialData SummaryModule = "financialData"
+	// SummaryModuleDefaultKeyStatistics requests the key statistics, see DefaultKeyStatistics.
+	SummaryModuleDefaultKeyStatistics SummaryModule = "defaultKeyStatistics"
+	// SummaryModuleSummaryDetail requests the summary detail, see SummaryDetail.
+	SummaryModuleSummaryDetail SummaryModule = "summaryDetail"
+	// SummaryModuleCalendarEvents requests the upcoming earnings and dividends, see CalendarEvents.
+	SummaryModuleCalendarEvents SummaryModule = "calendarEvents"
+	// SummaryModuleEarnings requests the earnings history and estimates, see Earnings.
+	SummaryModuleEarnings SummaryModule = "earnings"
+	// SummaryModuleRecommendationTrend requests the analyst recommendation trend, see RecommendationTrend.
+	SummaryModuleRecommendationTrend SummaryModule = "recommendationTrend"
+	// SummaryModuleMajorHoldersBreakdown requests the breakdown of major holders, see MajorHoldersBreakdown.
+	SummaryModuleMajorHoldersBreakdown SummaryModule = "majorHoldersBreakdown"
+	// SummaryModuleInstitutionOwnership requests the top institutional holders, see Ownership.
+	SummaryModuleInstitutionOwnership SummaryModule = "institutionOwnership"
+	// SummaryModuleFundOwnership requests the top mutual fund holders, see Ownership.
+	SummaryModuleFundOwnership SummaryModule = "fundOwnership"
+)
+
+// SummaryValue is a numeric value of the quote summary API. Yahoo! finance wraps those values in an object holding
+// the raw value and its formatted representations, and sends an empty object when the value is not available.
+type SummaryValue struct {
+	// Raw is the value, or nil if it isn't available.
+	Raw *float64 `json:"raw,omitempty"`
+	// Fmt is the short formatted representation of the value, e.g. "2.89T".
+	Fmt string `json:"fmt,omitempty"`
+	// LongFmt is the long formatted representation of the value, e.g. "2,889,999,999,999". Not always present.
+	LongFmt string `json:"longFmt,omitempty"`
+}
+
+// UnmarshalJSON decodes a summary value, accepting bare numbers as well as {raw, fmt} objects.
+func (v *SummaryValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*v = SummaryValue{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '{' {
+		var raw float64
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		*v = SummaryValue{Raw: &raw}
+		return nil
+	}
+
+	// plainSummaryValue has no methods, so decoding into it doesn't recurse into this function.
+	type plainSummaryValue SummaryValue
+	*v = SummaryValue{}
+	return json.Unmarshal(data, (*plainSummaryValue)(v))
+}
+
+// Valid tells whether the value is available.
+func (v SummaryValue) Valid() bool {
+	return v.Raw != nil
+}
+
+// Float returns the value, or zero if it isn't available.
+func (v SummaryValue) Float() float64 {
+	if v.Raw == nil {
+		return 0
+	}
+	return *v.Raw
+}
+
+// Int returns the value truncated to an integer, or zero if it isn't available.
+func (v SummaryValue) Int() int64 {
+	return int64(v.Float())
+}
+
+// UnixTime returns the value as a timestamp, for date values, or zero if it isn't available.
+func (v SummaryValue) UnixTime() UnixTime {
+	return UnixTime(v.Int())
+}
+
+// QuoteSummary holds the modules returned by GetQuoteSummary. Only the requested modules are set.
+type QuoteSummary struct {
+	AssetProfile          *AssetProfile          `json:"assetProfile,omitempty"`
+	FinancialData         *FinancialData         `json:"financialData,omitempty"`
+	DefaultKeyStatistics  *DefaultKeyStatistics  `json:"defaultKeyStatistics,omitempty"`
+	SummaryDetail         *SummaryDetail         `json:"summaryDetail,omitempty"`
+	CalendarEvents        *CalendarEvents        `json:"calendarEvents,omitempty"`
+	Earnings              *Earnings              `json:"earnings,omitempty"`
+	RecommendationTrend   *RecommendationTrend   `json:"recommendationTrend,omitempty"`
+	MajorHoldersBreakdown *MajorHoldersBreakdown `json:"majorHoldersBreakdown,omitempty"`
+	InstitutionOwnership  *Ownership             `json:"institutionOwnership,omitempty"`
+	FundOwnership         *Ownership             `json:"fundOwnership,omitempty"`
+}
+
+// AssetProfile is the profile of a company: contact information, classification, description and officers.
+type AssetProfile struct {
+	Address1                  string           `json:"address1"`
+	Address2                  string           `json:"address2,omitempty"`
+	City                      string           `json:"city"`
+	State                     string           `json:"state,omitempty"`
+	Zip                       string           `json:"zip"`
+	Country                   string           `json:"country"`
+	Phone                     string           `json:"phone"`
+	Fax                       string           `json:"fax,omitempty"`
+	Website                   string           `json:"website"`
+	IRWebsite                 string           `json:"irWebsite,omitempty"`
+	Industry                  string           `json:"industry"`
+	IndustryKey               string           `json:"industryKey"`
+	Sector                    string           `json:"sector"`
+	SectorKey                 string           `json:"sectorKey"`
+	LongBusinessSummary       string           `json:"longBusinessSummary"`
+	FullTimeEmployees         int              `json:"fullTimeEmployees"`
+	CompanyOfficers           []CompanyOfficer `json:"companyOfficers"`
+	AuditRisk                 int              `json:"auditRisk"`
+	BoardRisk                 int              `json:"boardRisk"`
+	CompensationRisk          int              `json:"compensationRisk"`
+	ShareHolderRightsRisk     int              `json:"shareHolderRightsRisk"`
+	OverallRisk               int              `json:"overallRisk"`
+	GovernanceEpochDate       UnixTime         `json:"governanceEpochDate"`
+	CompensationAsOfEpochDate UnixTime         `json:"compensationAsOfEpochDate"`
+}
+
+// CompanyOfficer is an officer of a company, as listed in its AssetProfile.
+type CompanyOfficer struct {
+	Name             string       `json:"name"`
+	Title            string       `json:"title"`
+	Age              int          `json:"age,omitempty"`
+	YearBorn         int          `json:"yearBorn,omitempty"`
+	FiscalYear       int          `json:"fiscalYear,omitempty"`
+	TotalPay         SummaryValue `json:"totalPay"`
+	ExercisedValue   SummaryValue `json:"exercisedValue"`
+	UnexercisedValue SummaryValue `json:"unexercisedValue"`
+}
+
+// FinancialData holds current financial figures of a company along with analyst price targets.
+type FinancialData struct {
+	CurrentPrice            SummaryValue `json:"currentPrice"`
+	TargetHighPrice         SummaryValue `json:"targetHighPrice"`
+	TargetLowPrice          SummaryValue `json:"targetLowPrice"`
+	TargetMeanPrice         SummaryValue `json:"targetMeanPrice"`
+	TargetMedianPrice       SummaryValue `json:"targetMedianPrice"`
+	RecommendationMean      SummaryValue `json:"recommendationMean"`
+	RecommendationKey       string       `json:"recommendationKey"`
+	NumberOfAnalystOpinions SummaryValue `json:"numberOfAnalystOpinions"`
+	TotalCash               SummaryValue `json:"totalCash"`
+	TotalCashPerShare       SummaryValue `json:"totalCashPerShare"`
+	Ebitda                  SummaryValue `json:"ebitda"`
+	TotalDebt               SummaryValue `json:"totalDebt"`
+	QuickRatio              SummaryValue `json:"quickRatio"`
+	CurrentRatio            SummaryValue `json:"currentRatio"`
+	TotalRevenue            SummaryValue `json:"totalRevenue"`
+	DebtToEquity            SummaryValue `json:"debtToEquity"`
+	RevenuePerShare         SummaryValue `json:"revenuePerShare"`
+	ReturnOnAssets          SummaryValue `json:"returnOnAssets"`
+	ReturnOnEquity          SummaryValue `json:"returnOnEquity"`
+	GrossProfits            SummaryValue `json:"grossProfits"`
+	FreeCashflow            SummaryValue `json:"freeCashflow"`
+	OperatingCashflow       SummaryValue `json:"operatingCashflow"`
+	EarningsGrowth          SummaryValue `json:"earningsGrowth"`
+	RevenueGrowth           SummaryValue `json:"revenueGrowth"`
+	GrossMargins            SummaryValue `json:"grossMargins"`
+	EbitdaMargins           SummaryValue `json:"ebitdaMargins"`
+	OperatingMargins        SummaryValue `json:"operatingMargins"`
+	ProfitMargins           SummaryValue `json:"profitMargins"`
+	FinancialCurrency       string       `json:"financialCurrency"`
+}
+
+// DefaultKeyStatistics holds the key valuation, share and ownership statistics of a security.
+type DefaultKeyStatistics struct {
+	EnterpriseValue              SummaryValue `json:"enterpriseValue"`
+	ForwardPE                    SummaryValue `json:"forwardPE"`
+	ProfitMargins                SummaryValue `json:"profitMargins"`
+	FloatShares                  SummaryValue `json:"floatShares"`
+	SharesOutstanding            SummaryValue `json:"sharesOutstanding"`
+	SharesShort                  SummaryValue `json:"sharesShort"`
+	SharesShortPriorMonth        SummaryValue `json:"sharesShortPriorMonth"`
+	SharesShortPreviousMonthDate SummaryValue `json:"sharesShortPreviousMonthDate"`
+	DateShortInterest            SummaryValue `json:"dateShortInterest"`
+	SharesPercentSharesOut       SummaryValue `json:"sharesPercentSharesOut"`
+	HeldPercentInsiders          SummaryValue `json:"heldPercentInsiders"`
+	HeldPercentInstitutions      SummaryValue `json:"heldPercentInstitutions"`
+	ShortRatio                   SummaryValue `json:"shortRatio"`
+	ShortPercentOfFloat          SummaryValue `json:"shortPercentOfFloat"`
+	Beta                         SummaryValue `json:"beta"`
+	ImpliedSharesOutstanding     SummaryValue `json:"impliedSharesOutstanding"`
+	BookValue                    SummaryValue `json:"bookValue"`
+	PriceToBook                  SummaryValue `json:"priceToBook"`
+	FundFamily                   string       `json:"fundFamily,omitempty"`
+	LegalType                    string       `json:"legalType,omitempty"`
+	LastFiscalYearEnd            SummaryValue `json:"lastFiscalYearEnd"`
+	NextFiscalYearEnd            SummaryValue `json:"nextFiscalYearEnd"`
+	MostRecentQuarter            SummaryValue `json:"mostRecentQuarter"`
+	EarningsQuarterlyGrowth      SummaryValue `json:"earningsQuarterlyGrowth"`
+	NetIncomeToCommon            SummaryValue `json:"netIncomeToCommon"`
+	TrailingEps                  SummaryValue `json:"trailingEps"`
+	ForwardEps                   SummaryValue `json:"forwardEps"`
+	PegRatio                     SummaryValue `json:"pegRatio"`
+	LastSplitFactor              string       `json:"lastSplitFactor,omitempty"`
+	LastSplitDate                SummaryValue `json:"lastSplitDate"`
+	EnterpriseToRevenue          SummaryValue `json:"enterpriseToRevenue"`
+	EnterpriseToEbitda           SummaryValue `json:"enterpriseToEbitda"`
+	FiftyTwoWeekChange           SummaryValue `json:"52WeekChange"`
+	SandP52WeekChange            SummaryValue `json:"SandP52WeekChange"`
+	LastDividendValue            SummaryValue `json:"lastDividendValue"`
+	LastDividendDate             SummaryValue `json:"lastDividendDate"`
+}
+
+// SummaryDetail holds the trading and dividend figures shown on the summary page of a security.
+type SummaryDetail struct {
+	PreviousClose                SummaryValue `json:"previousClose"`
+	Open                         SummaryValue `json:"open"`
+	DayLow                       SummaryValue `json:"dayLow"`
+	DayHigh                      SummaryValue `json:"dayHigh"`
+	RegularMarketPreviousClose   SummaryValue `json:"regularMarketPreviousClose"`
+	RegularMarketOpen            SummaryValue `json:"regularMarketOpen"`
+	RegularMarketDayLow          SummaryValue `json:"regularMarketDayLow"`
+	RegularMarketDayHigh         SummaryValue `json:"regularMarketDayHigh"`
+	DividendRate                 SummaryValue `json:"dividendRate"`
+	DividendYield                SummaryValue `json:"dividendYield"`
+	ExDividendDate               SummaryValue `json:"exDividendDate"`
+	PayoutRatio                  SummaryValue `json:"payoutRatio"`
+	FiveYearAvgDividendYield     SummaryValue `json:"fiveYearAvgDividendYield"`
+	Beta                         SummaryValue `json:"beta"`
+	TrailingPE                   SummaryValue `json:"trailingPE"`
+	ForwardPE                    SummaryValue `json:"forwardPE"`
+	Volume                       SummaryValue `json:"volume"`
+	RegularMarketVolume          SummaryValue `json:"regularMarketVolume"`
+	AverageVolume                SummaryValue `json:"averageVolume"`
+	AverageVolume10Days          SummaryValue `json:"averageVolume10days"`
+	Bid                          SummaryValue `json:"bid"`
+	Ask                          SummaryValue `json:"ask"`
+	BidSize                      SummaryValue `json:"bidSize"`
+	AskSize                      SummaryValue `json:"askSize"`
+	MarketCap                    SummaryValue `json:"marketCap"`
+	FiftyTwoWeekLow              SummaryValue `json:"fiftyTwoWeekLow"`
+	FiftyTwoWeekHigh             SummaryValue `json:"fiftyTwoWeekHigh"`
+	PriceToSalesTrailing12Months SummaryValue `json:"priceToSalesTrailing12Months"`
+	FiftyDayAverage              SummaryValue `json:"fiftyDayAverage"`
+	TwoHundredDayAverage         SummaryValue `json:"twoHundredDayAverage"`
+	TrailingAnnualDividendRate   SummaryValue `json:"trailingAnnualDividendRate"`
+	TrailingAnnualDividendYield  SummaryValue `json:"trailingAnnualDividendYield"`
+	Currency                     string       `json:"currency"`
+	Tradeable                    bool         `json:"tradeable"`
+}
+
+// CalendarEvents holds the upcoming earnings and dividend events of a company.
+type CalendarEvents struct {
+	Earnings struct {
+		// EarningsDate holds one date, or two bounding the expected date when it isn't confirmed yet.
+		EarningsDate    []SummaryValue `json:"earningsDate"`
+		EarningsAverage SummaryValue   `json:"earningsAverage"`
+		EarningsLow     SummaryValue   `json:"earningsLow"`
+		EarningsHigh    SummaryValue   `json:"earningsHigh"`
+		RevenueAverage  SummaryValue   `json:"revenueAverage"`
+		RevenueLow      SummaryValue   `json:"revenueLow"`
+		RevenueHigh     SummaryValue   `json:"revenueHigh"`
+	} `json:"earnings"`
+	ExDividendDate SummaryValue `json:"exDividendDate"`
+	DividendDate   SummaryValue `json:"dividendDate"`
+}
+
+// Earnings holds the recent quarterly earnings of a company against estimates, and its yearly and quarterly
+// revenue and earnings.
+type Earnings struct {
+	EarningsChart struct {
+		Quarterly []struct {
+			// Date is the fiscal quarter, e.g. "3Q2023".
+			Date     string       `json:"date"`
+			Actual   SummaryValue `json:"actual"`
+			Estimate SummaryValue `json:"estimate"`
+		} `json:"quarterly"`
+		CurrentQuarterEstimate     SummaryValue   `json:"currentQuarterEstimate"`
+		CurrentQuarterEstimateDate string         `json:"currentQuarterEstimateDate"`
+		CurrentQuarterEstimateYear int            `json:"currentQuarterEstimateYear"`
+		EarningsDate               []SummaryValue `json:"earningsDate"`
+	} `json:"earningsChart"`
+	FinancialsChart struct {
+		Yearly []struct {
+			// Date is the fiscal year, e.g. 2023.
+			Date     int          `json:"date"`
+			Revenue  SummaryValue `json:"revenue"`
+			Earnings SummaryValue `json:"earnings"`
+		} `json:"yearly"`
+		Quarterly []struct {
+			// Date is the fiscal quarter, e.g. "3Q2023".
+			Date     string       `json:"date"`
+			Revenue  SummaryValue `json:"revenue"`
+			Earnings SummaryValue `json:"earnings"`
+		} `json:"quarterly"`
+	} `json:"financialsChart"`
+	FinancialCurrency string `json:"financialCurrency"`
+}
+
+// RecommendationTrend holds the count of analyst recommendations over the last few months.
+type RecommendationTrend struct {
+	Trend []struct {
+		// Period is the month the recommendations apply to, relative to the current one: "0m", "-1m", etc.
+		Period     string `json:"period"`
+		StrongBuy  int    `json:"strongBuy"`
+		Buy        int    `json:"buy"`
+		Hold       int    `json:"hold"`
+		Sell       int    `json:"sell"`
+		StrongSell int    `json:"strongSell"`
+	} `json:"trend"`
+}
+
+// MajorHoldersBreakdown holds the share of a company held by insiders and institutions.
+type MajorHoldersBreakdown struct {
+	InsidersPercentHeld          SummaryValue `json:"insidersPercentHeld"`
+	InstitutionsPercentHeld      SummaryValue `json:"institutionsPercentHeld"`
+	InstitutionsFloatPercentHeld SummaryValue `json:"institutionsFloatPercentHeld"`
+	InstitutionsCount            SummaryValue `json:"institutionsCount"`
+}
+
+// Ownership is the list of the top holders of a company, either institutions or mutual funds.
+type Ownership struct {
+	OwnershipList []struct {
+		ReportDate   SummaryValue `json:"reportDate"`
+		Organization string       `json:"organization"`
+		PctHeld      SummaryValue `json:"pctHeld"`
+		Position     SummaryValue `json:"position"`
+		Value        SummaryValue `json:"value"`
+		PctChange    SummaryValue `json:"pctChange"`
+	} `json:"ownershipList"`
+}
+
+// GetQuoteSummary returns the given modules of the quote summary of the given symbol.
+func GetQuoteSummary(symbol string, modules ...SummaryModule) (*QuoteSummary, error) {
+	if symbol == "" {
+		return nil, CreateArgumentError("No symbol provided to GetQuoteSummary")
+	}
+	if len(modules) == 0 {
+		return nil, CreateArgumentError("No modules provided to GetQuoteSummary")
+	}
+
+	names := make([]string, len(modules))
+	for i, m := range modules {
+		names[i] = string(m)
+	}
+
+	params := queryParams{"modules": strings.Join(names, ",")}
+	resp := quoteSummaryResponse{}
+
+	err := client.call(yFinQuoteSummaryAPI+url.PathEscape(symbol), params, &resp)
+	if err != nil {
+		return nil, createRemoteError(err)
+	}
+
+	if resp.Inner.Error != nil {
+		return nil, createRemoteError(resp.Inner.Error)
+	}
+	if len(resp.Inner.Result) == 0 {
+		return nil, createRemoteError(&yError{Code: "Not Found", Description: "No quote summary returned for " + symbol})
+	}
+
+	return &resp.Inner.Result[0], nil
+}
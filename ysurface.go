@@ -0,0 +1,197 @@
+package dorfyn
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// maxOptionFetches is the default number of expiration dates GetOptionSurface fetches concurrently.
+	maxOptionFetches = 4
+)
+
+// OptionSurface is the complete option chain of an underlying, across all its expiration dates.
+type OptionSurface struct {
+	Symbol string
+	// Underlying is the quote of the underlying at the time of the first request.
+	Underlying *Quote
+	// Expirations are the expiration dates whose chain was fetched, in chronological order.
+	Expirations []UnixTime
+	// Chains maps each expiration date to its chain.
+	Chains map[UnixTime]*OptionChain
+	// Errors maps the expiration dates whose chain couldn't be fetched to the error that occurred.
+	Errors map[UnixTime]error
+}
+
+// SurfacePoint is the implied volatility of an option at a given expiration date and strike.
+type SurfacePoint struct {
+	Expiration UnixTime
+	Strike     float64
+	// Years is the time to expiration, in years.
+	Years             float64
+	ImpliedVolatility float64
+}
+
+// GetOptionSurface returns the option chains of the given underlying symbol for all its expiration dates, fetching at
+// most concurrency of them at once, or 4 if concurrency isn't positive. A failure to fetch an expiration date doesn't
+// fail the whole surface: it's reported in the Errors of the result instead.
+func GetOptionSurface(symbol string, concurrency int) (*OptionSurface, error) {
+	first, err := GetOptionChain(symbol, 0)
+	if err != nil {
+		return nil, err
+	}
+	if concurrency <= 0 {
+		concurrency = maxOptionFetches
+	}
+
+	surface := &OptionSurface{
+		Symbol:     first.Meta.UnderlyingSymbol,
+		Underlying: first.Meta.Quote,
+		Chains:     map[UnixTime]*OptionChain{},
+		Errors:     map[UnixTime]error{},
+	}
+	// Underlyings with no listed options come back with no expiration date.
+	if first.Meta.ExpirationDate != 0 {
+		surface.Chains[first.Meta.ExpirationDate] = first
+	}
+
+	var remaining []UnixTime
+	for _, exp := range first.Meta.AllExpirationDates {
+		if exp != first.Meta.ExpirationDate {
+			remaining = append(remaining, exp)
+		}
+	}
+
+	chains := make([]*OptionChain, len(remaining))
+	errs := make([]error, len(remaining))
+	forEachConcurrently(len(remaining), concurrency, func(i int) {
+		chains[i], errs[i] = GetOptionChain(symbol, remaining[i])
+	})
+
+	for i, exp := range remaining {
+		if errs[i] != nil {
+			logError("Can't fetch %s options expiring on %v: %v\n", symbol, exp.Time(), errs[i])
+			surface.Errors[exp] = errs[i]
+			continue
+		}
+		surface.Chains[exp] = chains[i]
+	}
+
+	for exp := range surface.Chains {
+		surface.Expirations = append(surface.Expirations, exp)
+	}
+	sort.Slice(surface.Expirations, func(i, j int) bool { return surface.Expirations[i] < surface.Expirations[j] })
+
+	return surface, nil
+}
+
+// Contract returns the contract of the given type, expiration date and strike, or nil if there is none.
+func (surface *OptionSurface) Contract(optionType OptionType, expiration UnixTime, strike float64) *Contract {
+	chain, ok := surface.Chains[expiration]
+	if !ok {
+		return nil
+	}
+	s := chain.Straddle(strike)
+	if s == nil {
+		return nil
+	}
+	if optionType == OptionTypePut {
+		return s.Put
+	}
+	return s.Call
+}
+
+// VolatilitySurface returns the implied volatility of every contract of the given type in the surface, by expiration
+// date then strike. If params is nil, the implied volatilities reported by Yahoo! finance are used. Otherwise, they're
+// solved from the market prices of the contracts, and contracts for which that fails are left out.
+func (surface *OptionSurface) VolatilitySurface(optionType OptionType, params *PricingParams) []SurfacePoint {
+	var points []SurfacePoint
+	for _, exp := range surface.Expirations {
+		for _, s := range surface.Chains[exp].Straddles {
+			contract := s.Call
+			if optionType == OptionTypePut {
+				contract = s.Put
+			}
+			if contract == nil {
+				continue
+			}
+			if point, ok := surface.point(optionType, contract, params); ok {
+				points = append(points, point)
+			}
+		}
+	}
+	return points
+}
+
+// TermStructure returns the at-the-money implied volatility of each expiration date of the surface: the average of
+// the call and put implied volatilities at the strike nearest to the underlying price. params works as for
+// VolatilitySurface.
+func (surface *OptionSurface) TermStructure(params *PricingParams) []SurfacePoint {
+	var underlying float64
+	if surface.Underlying != nil && surface.Underlying.RegularMarketPrice != nil {
+		underlying = *surface.Underlying.RegularMarketPrice
+	}
+
+	var points []SurfacePoint
+	for _, exp := range surface.Expirations {
+		chain := surface.Chains[exp]
+		var atm *Straddle
+		for i := range chain.Straddles {
+			if atm == nil || math.Abs(chain.Straddles[i].Strike-underlying) < math.Abs(atm.Strike-underlying) {
+				atm = &chain.Straddles[i]
+			}
+		}
+		if atm == nil {
+			continue
+		}
+
+		var sum float64
+		var point SurfacePoint
+		n := 0
+		for _, leg := range []struct {
+			optionType OptionType
+			contract   *Contract
+		}{{OptionTypeCall, atm.Call}, {OptionTypePut, atm.Put}} {
+			if leg.contract == nil {
+				continue
+			}
+			if p, ok := surface.point(leg.optionType, leg.contract, params); ok {
+				point = p
+				sum += p.ImpliedVolatility
+				n++
+			}
+		}
+		if n > 0 {
+			point.ImpliedVolatility = sum / float64(n)
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// point returns the surface point of the given contract, and false if its implied volatility isn't available.
+func (surface *OptionSurface) point(optionType OptionType, contract *Contract, params *PricingParams) (SurfacePoint,
+	bool) {
+	pricing := PricingParams{}
+	if params != nil {
+		pricing = *params
+	}
+
+	point := SurfacePoint{
+		Expiration:        contract.Expiration,
+		Strike:            contract.Strike,
+		Years:             pricing.YearsToExpiration(contract.Expiration),
+		ImpliedVolatility: contract.ImpliedVolatility,
+	}
+	if params != nil {
+		if surface.Underlying == nil || surface.Underlying.RegularMarketPrice == nil {
+			return point, false
+		}
+		analysis, err := params.Analyze(contract, optionType, *surface.Underlying.RegularMarketPrice)
+		if err != nil {
+			return point, false
+		}
+		point.ImpliedVolatility = analysis.ImpliedVolatility
+	}
+	return point, point.ImpliedVolatility > 0
+}
@@ -0,0 +1,89 @@
+package dorfyn
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// UnixTime is a point in time expressed in seconds since the Unix epoch, as returned by Yahoo! finance.
+	UnixTime int64
+	// UnixMilliTime is a point in time expressed in milliseconds since the Unix epoch, as returned by Yahoo! finance.
+	UnixMilliTime int64
+)
+
+var (
+	// locations caches the time zones loaded by exchangeLocation, keyed by IANA name.
+	locations sync.Map
+)
+
+// Time returns the UTC time corresponding to t.
+func (t UnixTime) Time() time.Time {
+	return time.Unix(int64(t), 0).UTC()
+}
+
+// In returns the time corresponding to t in the given location.
+func (t UnixTime) In(loc *time.Location) time.Time {
+	return time.Unix(int64(t), 0).In(loc)
+}
+
+// Time returns the UTC time corresponding to t.
+func (t UnixMilliTime) Time() time.Time {
+	return time.UnixMilli(int64(t)).UTC()
+}
+
+// In returns the time corresponding to t in the given location.
+func (t UnixMilliTime) In(loc *time.Location) time.Time {
+	return time.UnixMilli(int64(t)).In(loc)
+}
+
+// NewUnixTime returns the UnixTime corresponding to t.
+func NewUnixTime(t time.Time) UnixTime {
+	return UnixTime(t.Unix())
+}
+
+// Location returns the time zone of the exchange the quote's security is traded on. It is derived from
+// ExchangeTimezoneName, falling back to a fixed zone built from GmtOffSetMilliseconds, and finally to UTC.
+func (q *Quote) Location() *time.Location {
+	var name, abbrev string
+	var offset *int
+	if q.ExchangeTimezoneName != nil {
+		name = *q.ExchangeTimezoneName
+	}
+	if q.ExchangeTimezoneShortName != nil {
+		abbrev = *q.ExchangeTimezoneShortName
+	}
+	if q.GmtOffSetMilliseconds != nil {
+		seconds := *q.GmtOffSetMilliseconds / 1000
+		offset = &seconds
+	}
+	return exchangeLocation(name, abbrev, offset)
+}
+
+// Location returns the time zone of the exchange the charted security is traded on. It is derived from
+// ExchangeTimezoneName, falling back to a fixed zone built from GMTOffset.
+func (meta *ChartMeta) Location() *time.Location {
+	return exchangeLocation(meta.ExchangeTimezoneName, meta.Timezone, &meta.GMTOffset)
+}
+
+// exchangeLocation returns the time zone with the given IANA name, or a fixed zone with the given abbreviation and
+// offset in seconds east of UTC if the name can't be loaded. It returns UTC when neither is available.
+func exchangeLocation(name string, abbrev string, offset *int) *time.Location {
+	if name != "" {
+		if loc, ok := locations.Load(name); ok {
+			return loc.(*time.Location)
+		}
+
+		loc, err := time.LoadLocation(name)
+		if err == nil {
+			locations.Store(name, loc)
+			return loc
+		}
+		logInfo("Can't load time zone %q: %v\n", name, err)
+	}
+
+	if offset != nil {
+		return time.FixedZone(abbrev, *offset)
+	}
+	return time.UTC
+}
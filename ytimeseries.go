@@ -0,0 +1,478 @@
+package dorfyn
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeseriesResponse is a yfin fundamentals time series response.
+type timeseriesResponse struct {
+	Inner struct {
+		Result []map[string]json.RawMessage `json:"result"`
+		Error  *yError                      `json:"error"`
+	} `json:"timeseries"`
+}
+
+// timeseriesMeta is the metadata of a single series of a fundamentals time series response.
+type timeseriesMeta struct {
+	Symbol []string `json:"symbol"`
+	Type   []string `json:"type"`
+}
+
+// timeseriesPoint is a single value of a fundamentals time series response.
+type timeseriesPoint struct {
+	AsOfDate      string       `json:"asOfDate"`
+	PeriodType    string       `json:"periodType"`
+	CurrencyCode  string       `json:"currencyCode"`
+	ReportedValue SummaryValue `json:"reportedValue"`
+}
+
+const (
+	// yFinTimeseriesAPI is the path to the Yahoo! finance fundamentals time series API. It must be followed by a symbol.
+	yFinTimeseriesAPI string = "/ws/fundamentals-timeseries/v1/finance/timeseries/"
+)
+
+type (
+	// StatementType identifies a financial statement.
+	StatementType string
+	// StatementFrequency is the reporting frequency of a financial statement.
+	StatementFrequency string
+	// LineItem is the name of a line item of a financial statement, as used by Yahoo! finance.
+	LineItem string
+)
+
+const (
+	// StatementIncome is the income statement.
+	StatementIncome StatementType = "income"
+	// StatementBalanceSheet is the balance sheet.
+	StatementBalanceSheet StatementType = "balance"
+	// StatementCashFlow is the cash-flow statement.
+	StatementCashFlow StatementType = "cashflow"
+
+	// FrequencyAnnual is for yearly statements.
+	FrequencyAnnual StatementFrequency = "annual"
+	// FrequencyQuarterly is for quarterly statements.
+	FrequencyQuarterly StatementFrequency = "quarterly"
+	// FrequencyTrailing is for trailing twelve months statements. Not available for balance sheets.
+	FrequencyTrailing StatementFrequency = "trailing"
+)
+
+const (
+	// LineItemTotalRevenue is the Total Revenue line item.
+	LineItemTotalRevenue LineItem = "TotalRevenue"
+	// LineItemOperatingRevenue is the Operating Revenue line item.
+	LineItemOperatingRevenue LineItem = "OperatingRevenue"
+	// LineItemCostOfRevenue is the Cost Of Revenue line item.
+	LineItemCostOfRevenue LineItem = "CostOfRevenue"
+	// LineItemGrossProfit is the Gross Profit line item.
+	LineItemGrossProfit LineItem = "GrossProfit"
+	// LineItemOperatingExpense is the Operating Expense line item.
+	LineItemOperatingExpense LineItem = "OperatingExpense"
+	// LineItemSellingGeneralAndAdministration is the Selling General And Administration line item.
+	LineItemSellingGeneralAndAdministration LineItem = "SellingGeneralAndAdministration"
+	// LineItemResearchAndDevelopment is the Research And Development line item.
+	LineItemResearchAndDevelopment LineItem = "ResearchAndDevelopment"
+	// LineItemOperatingIncome is the Operating Income line item.
+	LineItemOperatingIncome LineItem = "OperatingIncome"
+	// LineItemNetInterestIncome is the Net Interest Income line item.
+	LineItemNetInterestIncome LineItem = "NetInterestIncome"
+	// LineItemInterestIncome is the Interest Income line item.
+	LineItemInterestIncome LineItem = "InterestIncome"
+	// LineItemInterestExpense is the Interest Expense line item.
+	LineItemInterestExpense LineItem = "InterestExpense"
+	// LineItemOtherIncomeExpense is the Other Income Expense line item.
+	LineItemOtherIncomeExpense LineItem = "OtherIncomeExpense"
+	// LineItemPretaxIncome is the Pretax Income line item.
+	LineItemPretaxIncome LineItem = "PretaxIncome"
+	// LineItemTaxProvision is the Tax Provision line item.
+	LineItemTaxProvision LineItem = "TaxProvision"
+	// LineItemNetIncome is the Net Income line item.
+	LineItemNetIncome LineItem = "NetIncome"
+	// LineItemNetIncomeCommonStockholders is the Net Income Common Stockholders line item.
+	LineItemNetIncomeCommonStockholders LineItem = "NetIncomeCommonStockholders"
+	// LineItemBasicEPS is the Basic EPS line item.
+	LineItemBasicEPS LineItem = "BasicEPS"
+	// LineItemDilutedEPS is the Diluted EPS line item.
+	LineItemDilutedEPS LineItem = "DilutedEPS"
+	// LineItemBasicAverageShares is the Basic Average Shares line item.
+	LineItemBasicAverageShares LineItem = "BasicAverageShares"
+	// LineItemDilutedAverageShares is the Diluted Average Shares line item.
+	LineItemDilutedAverageShares LineItem = "DilutedAverageShares"
+	// LineItemTotalExpenses is the Total Expenses line item.
+	LineItemTotalExpenses LineItem = "TotalExpenses"
+	// LineItemEBIT is the EBIT line item.
+	LineItemEBIT LineItem = "EBIT"
+	// LineItemEBITDA is the EBITDA line item.
+	LineItemEBITDA LineItem = "EBITDA"
+	// LineItemNormalizedEBITDA is the Normalized EBITDA line item.
+	LineItemNormalizedEBITDA LineItem = "NormalizedEBITDA"
+	// LineItemReconciledDepreciation is the Reconciled Depreciation line item.
+	LineItemReconciledDepreciation LineItem = "ReconciledDepreciation"
+
+	// LineItemTotalAssets is the Total Assets line item.
+	LineItemTotalAssets LineItem = "TotalAssets"
+	// LineItemCurrentAssets is the Current Assets line item.
+	LineItemCurrentAssets LineItem = "CurrentAssets"
+	// LineItemCashAndCashEquivalents is the Cash And Cash Equivalents line item.
+	LineItemCashAndCashEquivalents LineItem = "CashAndCashEquivalents"
+	// LineItemCashCashEquivalentsAndShortTermInvestments is the Cash Cash Equivalents And Short Term Investments line item.
+	LineItemCashCashEquivalentsAndShortTermInvestments LineItem = "CashCashEquivalentsAndShortTermInvestments"
+	// LineItemAccountsReceivable is the Accounts Receivable line item.
+	LineItemAccountsReceivable LineItem = "AccountsReceivable"
+	// LineItemInventory is the Inventory line item.
+	LineItemInventory LineItem = "Inventory"
+	// LineItemTotalNonCurrentAssets is the Total Non Current Assets line item.
+	LineItemTotalNonCurrentAssets LineItem = "TotalNonCurrentAssets"
+	// LineItemNetPPE is the Net PPE line item.
+	LineItemNetPPE LineItem = "NetPPE"
+	// LineItemGoodwill is the Goodwill line item.
+	LineItemGoodwill LineItem = "Goodwill"
+	// LineItemTotalLiabilitiesNetMinorityInterest is the Total Liabilities Net Minority Interest line item.
+	LineItemTotalLiabilitiesNetMinorityInterest LineItem = "TotalLiabilitiesNetMinorityInterest"
+	// LineItemCurrentLiabilities is the Current Liabilities line item.
+	LineItemCurrentLiabilities LineItem = "CurrentLiabilities"
+	// LineItemAccountsPayable is the Accounts Payable line item.
+	LineItemAccountsPayable LineItem = "AccountsPayable"
+	// LineItemCurrentDebt is the Current Debt line item.
+	LineItemCurrentDebt LineItem = "CurrentDebt"
+	// LineItemLongTermDebt is the Long Term Debt line item.
+	LineItemLongTermDebt LineItem = "LongTermDebt"
+	// LineItemTotalDebt is the Total Debt line item.
+	LineItemTotalDebt LineItem = "TotalDebt"
+	// LineItemNetDebt is the Net Debt line item.
+	LineItemNetDebt LineItem = "NetDebt"
+	// LineItemStockholdersEquity is the Stockholders Equity line item.
+	LineItemStockholdersEquity LineItem = "StockholdersEquity"
+	// LineItemTotalEquityGrossMinorityInterest is the Total Equity Gross Minority Interest line item.
+	LineItemTotalEquityGrossMinorityInterest LineItem = "TotalEquityGrossMinorityInterest"
+	// LineItemRetainedEarnings is the Retained Earnings line item.
+	LineItemRetainedEarnings LineItem = "RetainedEarnings"
+	// LineItemCommonStock is the Common Stock line item.
+	LineItemCommonStock LineItem = "CommonStock"
+	// LineItemWorkingCapital is the Working Capital line item.
+	LineItemWorkingCapital LineItem = "WorkingCapital"
+	// LineItemTangibleBookValue is the Tangible Book Value line item.
+	LineItemTangibleBookValue LineItem = "TangibleBookValue"
+	// LineItemInvestedCapital is the Invested Capital line item.
+	LineItemInvestedCapital LineItem = "InvestedCapital"
+	// LineItemShareIssued is the Share Issued line item.
+	LineItemShareIssued LineItem = "ShareIssued"
+	// LineItemOrdinarySharesNumber is the Ordinary Shares Number line item.
+	LineItemOrdinarySharesNumber LineItem = "OrdinarySharesNumber"
+
+	// LineItemOperatingCashFlow is the Operating Cash Flow line item.
+	LineItemOperatingCashFlow LineItem = "OperatingCashFlow"
+	// LineItemInvestingCashFlow is the Investing Cash Flow line item.
+	LineItemInvestingCashFlow LineItem = "InvestingCashFlow"
+	// LineItemFinancingCashFlow is the Financing Cash Flow line item.
+	LineItemFinancingCashFlow LineItem = "FinancingCashFlow"
+	// LineItemFreeCashFlow is the Free Cash Flow line item.
+	LineItemFreeCashFlow LineItem = "FreeCashFlow"
+	// LineItemCapitalExpenditure is the Capital Expenditure line item.
+	LineItemCapitalExpenditure LineItem = "CapitalExpenditure"
+	// LineItemBeginningCashPosition is the Beginning Cash Position line item.
+	LineItemBeginningCashPosition LineItem = "BeginningCashPosition"
+	// LineItemEndCashPosition is the End Cash Position line item.
+	LineItemEndCashPosition LineItem = "EndCashPosition"
+	// LineItemChangesInCash is the Changes In Cash line item.
+	LineItemChangesInCash LineItem = "ChangesInCash"
+	// LineItemNetIncomeFromContinuingOperations is the Net Income From Continuing Operations line item.
+	LineItemNetIncomeFromContinuingOperations LineItem = "NetIncomeFromContinuingOperations"
+	// LineItemDepreciationAndAmortization is the Depreciation And Amortization line item.
+	LineItemDepreciationAndAmortization LineItem = "DepreciationAndAmortization"
+	// LineItemStockBasedCompensation is the Stock Based Compensation line item.
+	LineItemStockBasedCompensation LineItem = "StockBasedCompensation"
+	// LineItemChangeInWorkingCapital is the Change In Working Capital line item.
+	LineItemChangeInWorkingCapital LineItem = "ChangeInWorkingCapital"
+	// LineItemIssuanceOfDebt is the Issuance Of Debt line item.
+	LineItemIssuanceOfDebt LineItem = "IssuanceOfDebt"
+	// LineItemRepaymentOfDebt is the Repayment Of Debt line item.
+	LineItemRepaymentOfDebt LineItem = "RepaymentOfDebt"
+	// LineItemRepurchaseOfCapitalStock is the Repurchase Of Capital Stock line item.
+	LineItemRepurchaseOfCapitalStock LineItem = "RepurchaseOfCapitalStock"
+	// LineItemCashDividendsPaid is the Cash Dividends Paid line item.
+	LineItemCashDividendsPaid LineItem = "CashDividendsPaid"
+)
+
+var (
+	// statementLineItems are the line items requested by default for each statement type.
+	statementLineItems = map[StatementType][]LineItem{
+		StatementIncome: {
+			LineItemTotalRevenue,
+			LineItemOperatingRevenue,
+			LineItemCostOfRevenue,
+			LineItemGrossProfit,
+			LineItemOperatingExpense,
+			LineItemSellingGeneralAndAdministration,
+			LineItemResearchAndDevelopment,
+			LineItemOperatingIncome,
+			LineItemNetInterestIncome,
+			LineItemInterestIncome,
+			LineItemInterestExpense,
+			LineItemOtherIncomeExpense,
+			LineItemPretaxIncome,
+			LineItemTaxProvision,
+			LineItemNetIncome,
+			LineItemNetIncomeCommonStockholders,
+			LineItemBasicEPS,
+			LineItemDilutedEPS,
+			LineItemBasicAverageShares,
+			LineItemDilutedAverageShares,
+			LineItemTotalExpenses,
+			LineItemEBIT,
+			LineItemEBITDA,
+			LineItemNormalizedEBITDA,
+			LineItemReconciledDepreciation,
+		},
+		StatementBalanceSheet: {
+			LineItemTotalAssets,
+			LineItemCurrentAssets,
+			LineItemCashAndCashEquivalents,
+			LineItemCashCashEquivalentsAndShortTermInvestments,
+			LineItemAccountsReceivable,
+			LineItemInventory,
+			LineItemTotalNonCurrentAssets,
+			LineItemNetPPE,
+			LineItemGoodwill,
+			LineItemTotalLiabilitiesNetMinorityInterest,
+			LineItemCurrentLiabilities,
+			LineItemAccountsPayable,
+			LineItemCurrentDebt,
+			LineItemLongTermDebt,
+			LineItemTotalDebt,
+			LineItemNetDebt,
+			LineItemStockholdersEquity,
+			LineItemTotalEquityGrossMinorityInterest,
+			LineItemRetainedEarnings,
+			LineItemCommonStock,
+			LineItemWorkingCapital,
+			LineItemTangibleBookValue,
+			LineItemInvestedCapital,
+			LineItemShareIssued,
+			LineItemOrdinarySharesNumber,
+		},
+		StatementCashFlow: {
+			LineItemOperatingCashFlow,
+			LineItemInvestingCashFlow,
+			LineItemFinancingCashFlow,
+			LineItemFreeCashFlow,
+			LineItemCapitalExpenditure,
+			LineItemBeginningCashPosition,
+			LineItemEndCashPosition,
+			LineItemChangesInCash,
+			LineItemNetIncomeFromContinuingOperations,
+			LineItemDepreciationAndAmortization,
+			LineItemStockBasedCompensation,
+			LineItemChangeInWorkingCapital,
+			LineItemIssuanceOfDebt,
+			LineItemRepaymentOfDebt,
+			LineItemRepurchaseOfCapitalStock,
+			LineItemCashDividendsPaid,
+		},
+	}
+
+	// timeseriesEpoch is the default start of the period range of GetFinancials, earlier than any data Yahoo! has.
+	timeseriesEpoch = time.Date(1985, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// FinancialsParams holds the options of a GetFinancials call. The zero value requests the annual figures of all the
+// default line items of the statement, over all available years.
+type FinancialsParams struct {
+	// Frequency is the reporting frequency. Defaults to FrequencyAnnual.
+	Frequency StatementFrequency
+	// Start is the beginning of the period range. Defaults to the earliest available data.
+	Start time.Time
+	// End is the end of the period range. Defaults to now.
+	End time.Time
+	// Items are the line items to fetch. Defaults to the most common line items of the statement.
+	Items []LineItem
+}
+
+// FinancialPoint is the value of a line item for a reporting period.
+type FinancialPoint struct {
+	// Date is the end of the reporting period.
+	Date time.Time
+	// Value is the reported value.
+	Value float64
+	// PeriodType is the length of the reporting period, e.g. "12M" or "3M".
+	PeriodType string
+	// Currency is the currency of the value, or empty for unitless values such as share counts.
+	Currency string
+}
+
+// FinancialSeries is the values of a line item, sorted by date.
+type FinancialSeries []FinancialPoint
+
+// FinancialStatement is a financial statement of a company, as a set of date-indexed line item series.
+type FinancialStatement struct {
+	Symbol    string
+	Type      StatementType
+	Frequency StatementFrequency
+	// Items maps each line item to its values. Line items Yahoo! finance has no data for are absent.
+	Items map[LineItem]FinancialSeries
+}
+
+// At returns the value of the series for the reporting period ending on the given date.
+func (s FinancialSeries) At(date time.Time) (float64, bool) {
+	i := sort.Search(len(s), func(i int) bool { return !s[i].Date.Before(date) })
+	if i < len(s) && s[i].Date.Equal(date) {
+		return s[i].Value, true
+	}
+	return 0, false
+}
+
+// Latest returns the most recent value of the series.
+func (s FinancialSeries) Latest() (FinancialPoint, bool) {
+	if len(s) == 0 {
+		return FinancialPoint{}, false
+	}
+	return s[len(s)-1], true
+}
+
+// Dates returns the end dates of all the reporting periods of the statement, in ascending order.
+func (fs *FinancialStatement) Dates() []time.Time {
+	seen := map[time.Time]bool{}
+	var dates []time.Time
+	for _, series := range fs.Items {
+		for _, p := range series {
+			if !seen[p.Date] {
+				seen[p.Date] = true
+				dates = append(dates, p.Date)
+			}
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+// Value returns the value of the given line item for the reporting period ending on the given date.
+func (fs *FinancialStatement) Value(item LineItem, date time.Time) (float64, bool) {
+	return fs.Items[item].At(date)
+}
+
+// GetFinancials returns the given financial statement of the given symbol. params may be nil to use the default
+// options.
+func GetFinancials(symbol string, statement StatementType, params *FinancialsParams) (*FinancialStatement, error) {
+	if symbol == "" {
+		return nil, CreateArgumentError("No symbol provided to GetFinancials")
+	}
+	defaults, ok := statementLineItems[statement]
+	if !ok {
+		return nil, CreateArgumentError("Unknown statement type provided to GetFinancials: " + string(statement))
+	}
+	if params == nil {
+		params = &FinancialsParams{}
+	}
+
+	frequency := params.Frequency
+	if frequency == "" {
+		frequency = FrequencyAnnual
+	}
+	if frequency != FrequencyAnnual && frequency != FrequencyQuarterly && frequency != FrequencyTrailing {
+		return nil, CreateArgumentError("Unknown frequency provided to GetFinancials: " + string(frequency))
+	}
+	if frequency == FrequencyTrailing && statement == StatementBalanceSheet {
+		return nil, CreateArgumentError("Trailing frequency provided to GetFinancials for a balance sheet, which has none")
+	}
+
+	start, end := params.Start, params.End
+	if start.IsZero() {
+		start = timeseriesEpoch
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if !start.Before(end) {
+		return nil, CreateArgumentError("Empty period range provided to GetFinancials")
+	}
+
+	items := params.Items
+	if len(items) == 0 {
+		items = defaults
+	}
+	types := make([]string, len(items))
+	for i, item := range items {
+		types[i] = string(frequency) + string(item)
+	}
+
+	qp := queryParams{
+		"symbol":  symbol,
+		"type":    strings.Join(types, ","),
+		"period1": strconv.FormatInt(start.Unix(), 10),
+		"period2": strconv.FormatInt(end.Unix(), 10),
+	}
+	resp := timeseriesResponse{}
+
+	err := client.call(yFinTimeseriesAPI+url.PathEscape(symbol), qp, &resp)
+	if err != nil {
+		return nil, createRemoteError(err)
+	}
+	if resp.Inner.Error != nil {
+		return nil, createRemoteError(resp.Inner.Error)
+	}
+
+	financials := &FinancialStatement{
+		Symbol:    symbol,
+		Type:      statement,
+		Frequency: frequency,
+		Items:     map[LineItem]FinancialSeries{},
+	}
+
+	for _, result := range resp.Inner.Result {
+		var meta timeseriesMeta
+		if err := json.Unmarshal(result["meta"], &meta); err != nil {
+			logError("Can't decode time series metadata: %v\n", err)
+			continue
+		}
+		if len(meta.Type) == 0 {
+			logError("Ignoring time series without a type in its metadata\n")
+			continue
+		}
+
+		key := meta.Type[0]
+		raw, ok := result[key]
+		if !ok {
+			// No data for this line item.
+			continue
+		}
+
+		var points []*timeseriesPoint
+		if err := json.Unmarshal(raw, &points); err != nil {
+			logError("Can't decode time series %s: %v\n", key, err)
+			return nil, createRemoteError(err)
+		}
+
+		var series FinancialSeries
+		for _, p := range points {
+			// Yahoo! finance uses null for periods without a reported value.
+			if p == nil || !p.ReportedValue.Valid() {
+				continue
+			}
+
+			date, err := time.Parse(time.DateOnly, p.AsOfDate)
+			if err != nil {
+				logError("Can't parse time series date %q: %v\n", p.AsOfDate, err)
+				continue
+			}
+
+			series = append(series, FinancialPoint{
+				Date:       date,
+				Value:      p.ReportedValue.Float(),
+				PeriodType: p.PeriodType,
+				Currency:   p.CurrencyCode,
+			})
+		}
+		if len(series) == 0 {
+			continue
+		}
+
+		sort.Slice(series, func(i, j int) bool { return series[i].Date.Before(series[j].Date) })
+		financials.Items[LineItem(strings.TrimPrefix(key, string(frequency)))] = series
+	}
+
+	return financials, nil
+}
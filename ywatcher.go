@@ -0,0 +1,379 @@
+package dorfyn
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultWatchInterval is the default polling interval during regular market hours.
+	defaultWatchInterval = 15 * time.Second
+	// defaultWatchExtendedInterval is the default polling interval during pre and post market hours.
+	defaultWatchExtendedInterval = time.Minute
+	// defaultWatchClosedInterval is the default polling interval when the markets are closed.
+	defaultWatchClosedInterval = 5 * time.Minute
+	// defaultWatchBuffer is the default capacity of the subscribers' channels.
+	defaultWatchBuffer = 64
+)
+
+var (
+	// quoteFieldIndexes maps the JSON names of the Quote fields to their index in the struct.
+	quoteFieldIndexes = func() map[QuoteField]int {
+		indexes := map[QuoteField]int{}
+		t := reflect.TypeOf(Quote{})
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				indexes[QuoteField(name)] = i
+			}
+		}
+		return indexes
+	}()
+)
+
+// FieldChange is the change of a field between two successive quotes of a security. Old and New are the values of
+// the field, dereferenced, or nil if the field is absent from the quote.
+type FieldChange struct {
+	Field QuoteField
+	Old   any
+	New   any
+}
+
+// QuoteUpdate is a change in the quote of a watched security.
+type QuoteUpdate struct {
+	Symbol string
+	// Quote is the new quote of the security.
+	Quote Quote
+	// Previous is the previous quote of the security, or nil for the first quote received.
+	Previous *Quote
+	// Changes are the fields that differ between Previous and Quote. For the first quote, all its fields are
+	// reported, with a nil Old value.
+	Changes []FieldChange
+	// Time is the time at which the quote was polled.
+	Time time.Time
+}
+
+// Changed returns the change of the given field, and false if that field didn't change.
+func (u *QuoteUpdate) Changed(field QuoteField) (FieldChange, bool) {
+	for _, c := range u.Changes {
+		if c.Field == field {
+			return c, true
+		}
+	}
+	return FieldChange{}, false
+}
+
+// WatcherParams are the parameters of a quote watcher.
+type WatcherParams struct {
+	// Interval is the polling interval when at least one of the watched markets is in its regular session. Defaults to
+	// 15 seconds.
+	Interval time.Duration
+	// ExtendedInterval is the polling interval when at least one of the watched markets is in its pre or post market
+	// session, and none is in its regular one. Defaults to one minute.
+	ExtendedInterval time.Duration
+	// ClosedInterval is the polling interval when all the watched markets are closed. Defaults to five minutes.
+	ClosedInterval time.Duration
+	// Fields limits the polled and compared fields. Defaults to all fields.
+	Fields []QuoteField
+	// Buffer is the capacity of the subscribers' channels. Defaults to 64.
+	Buffer int
+}
+
+// Watcher polls the quotes of a set of securities and notifies its subscribers of the fields that changed between
+// polls. Unlike Stream, it only relies on GetQuotes, and works where websockets are blocked.
+type Watcher struct {
+	params WatcherParams
+
+	mu          sync.Mutex
+	symbols     []string
+	snapshots   map[string]Quote
+	subscribers []chan QuoteUpdate
+	callbacks   []func(QuoteUpdate)
+	// stopped tells whether the subscribers' channels have been closed.
+	stopped bool
+
+	errors chan error
+	wake   chan struct{}
+	done   chan struct{}
+	closed sync.Once
+	wg     sync.WaitGroup
+}
+
+// NewWatcher starts watching the given symbols. The first poll happens right away; use Subscribe or OnUpdate to
+// receive the updates, and Close to stop watching.
+func NewWatcher(symbols []string, params *WatcherParams) (*Watcher, error) {
+	if len(symbols) == 0 {
+		return nil, CreateArgumentError("No symbols provided to NewWatcher")
+	}
+
+	w := &Watcher{
+		snapshots: map[string]Quote{},
+		errors:    make(chan error, 16),
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	if params != nil {
+		w.params = *params
+	}
+	if w.params.Interval <= 0 {
+		w.params.Interval = defaultWatchInterval
+	}
+	if w.params.ExtendedInterval <= 0 {
+		w.params.ExtendedInterval = defaultWatchExtendedInterval
+	}
+	if w.params.ClosedInterval <= 0 {
+		w.params.ClosedInterval = defaultWatchClosedInterval
+	}
+	if w.params.Buffer <= 0 {
+		w.params.Buffer = defaultWatchBuffer
+	}
+	if len(w.params.Fields) > 0 {
+		w.params.Fields = append(w.params.Fields[:len(w.params.Fields):len(w.params.Fields)], QuoteFieldMarketState)
+	}
+	w.add(symbols)
+
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// Subscribe returns a channel on which the updates are delivered. The watcher waits for subscribers to receive their
+// updates; a subscriber that stops reading stalls the watcher. The channel is closed when the watcher is closed, and
+// is returned already closed if it is.
+func (w *Watcher) Subscribe() <-chan QuoteUpdate {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ch := make(chan QuoteUpdate, w.params.Buffer)
+	if w.stopped {
+		close(ch)
+		return ch
+	}
+	w.subscribers = append(w.subscribers, ch)
+	return ch
+}
+
+// OnUpdate registers a callback called with each update. Callbacks are called sequentially from the polling
+// goroutine, and must not block.
+func (w *Watcher) OnUpdate(callback func(QuoteUpdate)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, callback)
+}
+
+// Errors returns the channel on which polling errors are reported. Errors are dropped if the channel is not drained.
+// It is closed when the watcher is closed.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Add adds the given symbols to the watched ones, and polls right away.
+func (w *Watcher) Add(symbols ...string) {
+	w.mu.Lock()
+	w.add(symbols)
+	w.mu.Unlock()
+	w.poke()
+}
+
+// Remove removes the given symbols from the watched ones.
+func (w *Watcher) Remove(symbols ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		for i, s := range w.symbols {
+			if s == symbol {
+				w.symbols = append(w.symbols[:i], w.symbols[i+1:]...)
+				break
+			}
+		}
+		delete(w.snapshots, symbol)
+	}
+}
+
+// Symbols returns the watched symbols.
+func (w *Watcher) Symbols() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.symbols...)
+}
+
+// Close stops the watcher and closes its channels.
+func (w *Watcher) Close() {
+	w.closed.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+		w.mu.Lock()
+		for _, ch := range w.subscribers {
+			close(ch)
+		}
+		w.subscribers = nil
+		w.stopped = true
+		w.mu.Unlock()
+		close(w.errors)
+	})
+}
+
+// add adds the given symbols to the watched ones, skipping those already watched. Must be called with w.mu held.
+func (w *Watcher) add(symbols []string) {
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		found := false
+		for _, s := range w.symbols {
+			found = found || s == symbol
+		}
+		if !found {
+			w.symbols = append(w.symbols, symbol)
+		}
+	}
+}
+
+// poke makes the watcher poll right away.
+func (w *Watcher) poke() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run polls the quotes until the watcher is closed.
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	for {
+		interval := w.poll()
+		select {
+		case <-w.done:
+			return
+		default:
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-w.done:
+			timer.Stop()
+			return
+		case <-w.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// poll fetches the quotes, notifies the changes, and returns the delay until the next poll.
+func (w *Watcher) poll() time.Duration {
+	symbols := w.Symbols()
+	if len(symbols) == 0 {
+		return w.params.ClosedInterval
+	}
+
+	quotes, err := GetQuotesFields(symbols, w.params.Fields...)
+	now := time.Now()
+	if err != nil {
+		logError("Watcher poll failed: %v\n", err)
+		select {
+		case w.errors <- err:
+		default:
+		}
+		if len(quotes) == 0 {
+			return w.params.Interval
+		}
+	}
+
+	var updates []QuoteUpdate
+	w.mu.Lock()
+	for _, q := range quotes {
+		if q.Symbol == nil {
+			continue
+		}
+		symbol := strings.ToUpper(*q.Symbol)
+		previous, seen := w.snapshots[symbol]
+		if !seen && !w.watches(symbol) {
+			continue
+		}
+		w.snapshots[symbol] = q
+
+		update := QuoteUpdate{Symbol: symbol, Quote: q, Time: now}
+		if seen {
+			update.Previous = &previous
+			update.Changes = diffQuotes(&previous, &q)
+		} else {
+			update.Changes = diffQuotes(&Quote{}, &q)
+		}
+		if len(update.Changes) > 0 {
+			updates = append(updates, update)
+		}
+	}
+	subscribers := append([]chan QuoteUpdate(nil), w.subscribers...)
+	callbacks := append([]func(QuoteUpdate){}, w.callbacks...)
+	w.mu.Unlock()
+
+	for _, update := range updates {
+		for _, callback := range callbacks {
+			callback(update)
+		}
+		for _, ch := range subscribers {
+			select {
+			case ch <- update:
+			case <-w.done:
+				return 0
+			}
+		}
+	}
+
+	return w.interval(quotes)
+}
+
+// watches returns whether the given symbol is watched. Must be called with w.mu held.
+func (w *Watcher) watches(symbol string) bool {
+	for _, s := range w.symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// interval returns the polling interval suited to the market states of the given quotes.
+func (w *Watcher) interval(quotes []Quote) time.Duration {
+	interval := w.params.ClosedInterval
+	for _, q := range quotes {
+		if q.MarketState == nil {
+			continue
+		}
+		switch *q.MarketState {
+		case MarketStateRegular:
+			return w.params.Interval
+		case MarketStatePre, MarketStatePrePre, MarketStatePost, MarketStatePostPost:
+			interval = w.params.ExtendedInterval
+		}
+	}
+	return interval
+}
+
+// diffQuotes returns the fields that differ between the given quotes.
+func diffQuotes(old *Quote, new *Quote) []FieldChange {
+	var changes []FieldChange
+	o, n := reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem()
+	for field, i := range quoteFieldIndexes {
+		oldValue, newValue := fieldValue(o.Field(i)), fieldValue(n.Field(i))
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// fieldValue returns the dereferenced value of the given field, or nil if it is a nil pointer.
+func fieldValue(v reflect.Value) any {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}